@@ -0,0 +1,314 @@
+package compton
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/boggydigital/compton/consts/attr"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Fetcher returns content of the resource the page refers to
+// (images, iframe pages) for the archive (see WriteArchive)
+type Fetcher func(src string) ([]byte, error)
+
+// FSFetcher reads resources from fsys, with src path (without the
+// leading slash, query and fragment) as the file name
+func FSFetcher(fsys fs.FS) Fetcher {
+	return func(src string) ([]byte, error) {
+		u, err := url.Parse(src)
+		if err != nil {
+			return nil, err
+		}
+		return fs.ReadFile(fsys, strings.TrimPrefix(path.Clean("/"+u.Path), "/"))
+	}
+}
+
+// HttpFetcher requests resources with the client, relative src URLs
+// are resolved against baseUrl. Nil client uses http.DefaultClient
+func HttpFetcher(client *http.Client, baseUrl string) Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(src string) ([]byte, error) {
+		base, err := url.Parse(baseUrl)
+		if err != nil {
+			return nil, err
+		}
+		u, err := base.Parse(src)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Get(u.String())
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, ErrStatus(resp.StatusCode, fmt.Errorf("get %s", u))
+		}
+
+		return io.ReadAll(resp.Body)
+	}
+}
+
+// WriteArchive writes the page as a single self-contained HTML file:
+// IframeExpandHost frames are replaced with the content of their pages
+// in a shadow root, images (including IssaImage posters and Video posters)
+// are inlined as data URIs and speculation rules are removed.
+// Resources are read with fetch. Styles and scripts of the pages that use
+// AssetStore are inlined from the store. Deferred elements are resolved
+// before that. The page is not changed, archive is written for its clone
+func WriteArchive(w io.Writer, p PageElement, fetch Fetcher) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	archive, ok := p.Clone().(PageElement)
+	if !ok {
+		return fmt.Errorf("archive: page clone is not a page")
+	}
+
+	if pe, ok := archive.(*pageElement); ok {
+		// deferred elements content (and the styles and scripts
+		// registered by their producers) is inlined as well
+		pe.resolveDeferred()
+		if pe.assets != nil {
+			if err := inlineAssets(pe); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := inlineFrames(archive, archive, fetch); err != nil {
+		return err
+	}
+
+	if err := inlineImages(archive, "", fetch); err != nil {
+		return err
+	}
+
+	for _, script := range archive.QuerySelectorAll("script[type=" + speculationRulesName + "]") {
+		if parent := script.Parent(); parent != nil {
+			parent.RemoveChild(script)
+		}
+	}
+
+	return archive.Write(w)
+}
+
+// inlineFrames replaces IframeExpandHost elements with shadow hosts,
+// that contain styles and body content of the iframe pages.
+// Iframe pages scripts are not included
+func inlineFrames(r Registrar, el Element, fetch Fetcher) error {
+	for _, child := range el.GetChildren() {
+		ife, ok := child.(*IframeExpandElement)
+		if !ok {
+			if err := inlineFrames(r, child, fetch); err != nil {
+				return err
+			}
+			continue
+		}
+
+		src := ife.iframe.GetAttribute(attr.Src)
+		if src == "" {
+			continue
+		}
+
+		content, err := fetch(src)
+		if err != nil {
+			return fmt.Errorf("archive %s: %w", src, err)
+		}
+
+		styles, body, err := parseFrame(bytes.NewReader(content))
+		if err != nil {
+			return fmt.Errorf("archive %s: %w", src, err)
+		}
+
+		she := ShadowHost(r, EncapsulationOpen)
+		she.Append(styles...)
+		she.Append(body...)
+		if id := ife.iframe.GetAttribute(attr.Id); id != "" {
+			she.SetId(id)
+		}
+
+		// frame content images are relative to the frame
+		if err := inlineImages(she, src, fetch); err != nil {
+			return err
+		}
+
+		// components children methods may target their content elements,
+		// parent is the element that has the frame as a child
+		if parent := child.Parent(); parent != nil {
+			parent.ReplaceChild(she, child)
+		}
+	}
+	return nil
+}
+
+// parseFrame reads iframe page HTML document and returns
+// its head styles and body content, without scripts
+func parseFrame(r io.Reader) ([]Element, []Element, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var styles, body []Element
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			switch child.DataAtom {
+			case atom.Html:
+				walk(child)
+			case atom.Head:
+				for hc := child.FirstChild; hc != nil; hc = hc.NextSibling {
+					if hc.DataAtom == atom.Style {
+						styles = append(styles, convertNode(hc))
+					}
+				}
+			case atom.Body:
+				for bc := child.FirstChild; bc != nil; bc = bc.NextSibling {
+					if bc.DataAtom == atom.Script {
+						continue
+					}
+					if el := convertNode(bc); el != nil {
+						body = append(body, el)
+					}
+				}
+			}
+		}
+	}
+	walk(doc)
+
+	for _, el := range body {
+		removeScripts(el)
+	}
+
+	return styles, body, nil
+}
+
+func removeScripts(el Element) {
+	for _, child := range slices.Clone(el.GetChildren()) {
+		if child.GetTagName() == atom.Script {
+			el.RemoveChild(child)
+			continue
+		}
+		removeScripts(child)
+	}
+}
+
+// inlineImages replaces image src, IssaImage poster data-src and video
+// poster attributes values of the element descendants with data URIs.
+// Relative values are resolved against base URL, when it's not empty
+func inlineImages(el Element, base string, fetch Fetcher) error {
+	for _, name := range []string{attr.Src, attr.DataSrc, attr.Poster} {
+		selector := "img[" + name + "]"
+		if name == attr.Poster {
+			selector = "video[" + name + "]"
+		}
+		for _, img := range el.QuerySelectorAll(selector) {
+			src := img.GetAttribute(name)
+			if src == "" || strings.HasPrefix(src, "data:") {
+				continue
+			}
+
+			if base != "" {
+				resolved, err := resolveUrl(base, src)
+				if err != nil {
+					return fmt.Errorf("archive %s: %w", src, err)
+				}
+				src = resolved
+			}
+
+			content, err := fetch(src)
+			if err != nil {
+				return fmt.Errorf("archive %s: %w", src, err)
+			}
+
+			img.SetAttribute(name, dataUri(src, content))
+		}
+	}
+
+	return nil
+}
+
+func resolveUrl(base, ref string) (string, error) {
+	bu, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	ru, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return bu.ResolveReference(ru).String(), nil
+}
+
+// inlineAssets replaces stylesheet links and scripts served by the page AssetStore
+// with inline styles and scripts. Assets that are not in the store are errors
+func inlineAssets(p *pageElement) error {
+	for _, el := range p.QuerySelectorAll("link[href], script[src]") {
+		srcAttr := attr.Src
+		if el.GetTagName() == atom.Link {
+			if el.GetAttribute(attr.Rel) != attr.Stylesheet {
+				continue
+			}
+			srcAttr = attr.Href
+		}
+
+		src := el.GetAttribute(srcAttr)
+		if !strings.HasPrefix(src, p.assets.prefix) {
+			continue
+		}
+		content, ok := p.assets.Content(path.Base(src))
+		if !ok {
+			return fmt.Errorf("archive %s: %w", src, fs.ErrNotExist)
+		}
+
+		var inline Element
+		if el.GetTagName() == atom.Link {
+			inline = Style(content)
+		} else {
+			script := Script(content)
+			if be, ok := el.(*BaseElement); ok {
+				for _, name := range be.Attributes.names {
+					if name != attr.Src && name != attr.Integrity {
+						script.SetAttribute(name, be.GetAttribute(name))
+					}
+				}
+			}
+			inline = script
+		}
+
+		if parent := el.Parent(); parent != nil {
+			parent.ReplaceChild(inline, el)
+		}
+	}
+
+	p.assets = nil
+	return nil
+}
+
+func dataUri(src string, content []byte) string {
+	var contentType string
+	if u, err := url.Parse(src); err == nil {
+		contentType = mime.TypeByExtension(path.Ext(u.Path))
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(content)
+}
@@ -0,0 +1,86 @@
+package compton
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const archiveFrameHtml = `<!doctype html><html><head><style>p{color:red}</style></head>
+<body><p>frame</p><img src="frame.png"><script>alert(1)</script></body></html>`
+
+func TestWriteArchive(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.png":         {Data: []byte("page-png")},
+		"deferred.png":     {Data: []byte("deferred-png")},
+		"frames/f.html":    {Data: []byte(archiveFrameHtml)},
+		"frames/frame.png": {Data: []byte("frame-png")},
+	}
+
+	tests := []struct {
+		name     string
+		assets   bool
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "inline",
+			contains: []string{
+				"data:image/png;base64,cGFnZS1wbmc=",
+				"data:image/png;base64,ZGVmZXJyZWQtcG5n",
+				"data:image/png;base64,ZnJhbWUtcG5n",
+				"<p >frame</p>",
+				"p{color:red}",
+			},
+			excludes: []string{"'/page.png'", "'/deferred.png'", "'frame.png'", "alert(1)", "<iframe"},
+		},
+		{
+			name:     "asset store",
+			assets:   true,
+			contains: []string{"data:image/png;base64,ZnJhbWUtcG5n"},
+			excludes: []string{"/assets/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Page("archive")
+			if tt.assets {
+				p.UseAssetStore(NewAssetStore("/assets/"))
+			}
+			p.Append(Image("/page.png"), IframeExpandHost(p, "f", "/frames/f.html"))
+			p.Append(Deferred(p, func() Element { return Image("/deferred.png") }))
+			p.Append(Suspense(p, "s", nil, func(ctx context.Context, r Registrar) Element {
+				return IframeExpandHost(r, "sf", "/frames/f.html")
+			}))
+
+			buf := new(bytes.Buffer)
+			if err := WriteArchive(buf, p, FSFetcher(fsys)); err != nil {
+				t.Fatal(err)
+			}
+
+			archive := buf.String()
+			for _, s := range tt.contains {
+				if !strings.Contains(archive, s) {
+					t.Errorf("archive doesn't contain %q", s)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(archive, s) {
+					t.Errorf("archive contains %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteArchiveMissingResource(t *testing.T) {
+	p := Page("archive")
+	p.Append(Image("/missing.png"))
+
+	if err := WriteArchive(new(bytes.Buffer), p, FSFetcher(fstest.MapFS{})); err == nil {
+		t.Error("got no error for a missing image")
+	}
+}
@@ -0,0 +1,131 @@
+package compton
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	textCssContentType        = "text/css; charset=utf-8"
+	textJavascriptContentType = "text/javascript; charset=utf-8"
+	immutableCacheControl     = "public, max-age=31536000, immutable"
+)
+
+// hashed asset names use that many hex characters of content sha256
+const assetHashLength = 16
+
+type asset struct {
+	contentType string
+	content     []byte
+	etag        string
+}
+
+// AssetStore keeps styles and scripts registered by pages that use it
+// (see PageElement.UseAssetStore) and serves them as http.Handler.
+// Asset URLs include content hash, so they're served with immutable cache headers
+type AssetStore struct {
+	prefix string
+	assets map[string]*asset
+	mtx    sync.RWMutex
+}
+
+// NewAssetStore creates AssetStore with the URL prefix the store is served at,
+// e.g. "/assets/" with http.Handle("/assets/", store)
+func NewAssetStore(prefix string) *AssetStore {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &AssetStore{
+		prefix: prefix,
+		assets: make(map[string]*asset),
+	}
+}
+
+// Add stores content and returns content-hashed URL for it.
+// Name is only used as a readable part of the URL
+func (as *AssetStore) Add(name, contentType string, content []byte) string {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])[:assetHashLength]
+
+	ext := path.Ext(name)
+	hashedName := strings.TrimSuffix(path.Base(name), ext) + "." + hash + ext
+
+	as.mtx.Lock()
+	defer as.mtx.Unlock()
+
+	if _, ok := as.assets[hashedName]; !ok {
+		as.assets[hashedName] = &asset{
+			contentType: contentType,
+			content:     content,
+			etag:        strconv.Quote(hash),
+		}
+	}
+
+	return as.prefix + hashedName
+}
+
+// Names returns hashed names of all stored assets
+func (as *AssetStore) Names() []string {
+	as.mtx.RLock()
+	defer as.mtx.RUnlock()
+
+	return slices.Sorted(maps.Keys(as.assets))
+}
+
+// Content returns stored asset content by the hashed name
+func (as *AssetStore) Content(name string) ([]byte, bool) {
+	as.mtx.RLock()
+	defer as.mtx.RUnlock()
+
+	if a, ok := as.assets[name]; ok {
+		return a.content, true
+	}
+	return nil, false
+}
+
+func (as *AssetStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	as.mtx.RLock()
+	a, ok := as.assets[path.Base(r.URL.Path)]
+	as.mtx.RUnlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", a.contentType)
+	w.Header().Set("Cache-Control", immutableCacheControl)
+	w.Header().Set("ETag", a.etag)
+
+	if r.Header.Get("If-None-Match") == a.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(a.content)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(a.content)
+}
+
+// cspSource returns Content-Security-Policy source that allows store assets
+func (as *AssetStore) cspSource() string {
+	if strings.HasPrefix(as.prefix, "http://") || strings.HasPrefix(as.prefix, "https://") {
+		return as.prefix
+	}
+	return "'self'"
+}
@@ -3,20 +3,29 @@ package compton
 import (
 	"github.com/boggydigital/compton/consts/attr"
 	"io"
+	"slices"
 	"strings"
 )
 
+// Attributes preserve insertion order of attribute names,
+// so that two writes of the same element are identical
 type Attributes struct {
 	attributes map[string]string
+	names      []string
 }
 
+// SetAttribute ignores class attribute, class names are set with ClassList
+// methods (BaseElement.SetAttribute sets class names as well)
 func (a *Attributes) SetAttribute(name, val string) {
 	if name == attr.Class {
-		panic("class attribute should be set with ClassList methods")
+		return
 	}
 	if a.attributes == nil {
 		a.attributes = make(map[string]string)
 	}
+	if _, ok := a.attributes[name]; !ok {
+		a.names = append(a.names, name)
+	}
 	a.attributes[name] = val
 }
 
@@ -24,13 +33,29 @@ func (a *Attributes) GetAttribute(name string) string {
 	return a.attributes[name]
 }
 
+func (a *Attributes) HasAttribute(name string) bool {
+	_, ok := a.attributes[name]
+	return ok
+}
+
+func (a *Attributes) RemoveAttribute(name string) {
+	if _, ok := a.attributes[name]; ok {
+		delete(a.attributes, name)
+		a.names = slices.DeleteFunc(a.names, func(n string) bool { return n == name })
+	}
+}
+
 func (a *Attributes) Write(w io.Writer) error {
-	attrs := make([]string, 0, len(a.attributes))
-	for name, val := range a.attributes {
-		attrs = append(attrs, name+"='"+val+"'")
-		if _, err := w.Write([]byte(strings.Join(attrs, " "))); err != nil {
-			return err
-		}
+	attrs := make([]string, 0, len(a.names))
+	for _, name := range a.names {
+		attrs = append(attrs, formatAttribute(name, a.attributes[name]))
+	}
+	if _, err := io.WriteString(w, strings.Join(attrs, " ")); err != nil {
+		return err
 	}
 	return nil
 }
+
+func formatAttribute(name, val string) string {
+	return name + "='" + escapeAttributeValue(name, val) + "'"
+}
@@ -0,0 +1,66 @@
+package compton
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributesWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		set    [][2]string
+		remove []string
+		want   string
+	}{
+		{"empty", nil, nil, ""},
+		{"insertion order", [][2]string{{"z", "1"}, {"a", "2"}, {"m", "3"}}, nil, "z='1' a='2' m='3'"},
+		{"update keeps order", [][2]string{{"z", "1"}, {"a", "2"}, {"z", "3"}}, nil, "z='3' a='2'"},
+		{"class ignored", [][2]string{{"class", "c"}, {"id", "i"}}, nil, "id='i'"},
+		{"remove", [][2]string{{"z", "1"}, {"a", "2"}, {"m", "3"}}, []string{"a"}, "z='1' m='3'"},
+		{"remove and set", [][2]string{{"z", "1"}, {"a", "2"}}, []string{"z"}, "a='2'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attrs Attributes
+			for _, nv := range tt.set {
+				attrs.SetAttribute(nv[0], nv[1])
+			}
+			for _, name := range tt.remove {
+				attrs.RemoveAttribute(name)
+			}
+			buf := new(bytes.Buffer)
+			if err := attrs.Write(buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassListString(t *testing.T) {
+	tests := []struct {
+		name   string
+		add    []string
+		remove []string
+		want   string
+	}{
+		{"empty", nil, nil, ""},
+		{"insertion order", []string{"z", "a", "m"}, nil, "z a m"},
+		{"duplicates", []string{"z", "a", "z"}, nil, "z a"},
+		{"remove", []string{"z", "a", "m"}, []string{"a"}, "z m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cl ClassList
+			cl.AddClass(tt.add...)
+			cl.RemoveClass(tt.remove...)
+			if got := cl.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,14 +5,26 @@ import (
 	"github.com/boggydigital/compton/consts/attr"
 	"golang.org/x/net/html/atom"
 	"io"
+	"maps"
+	"slices"
+	"strings"
 )
 
+// BaseElement writes class attribute first, followed by other attributes
+// in the order they were set. Class names are written in the order they were added.
+// Components that write an inner element (e.g. details, template, iframe) append it
+// to their BaseElement as the only child, so that lookups and queries reach the
+// inner element and its content, while children methods target the inner element
+// (or a content element inside it)
 type BaseElement struct {
 	Attributes
 	ClassList
 	Children []Element
 	TagName  atom.Atom
 	MarkupProvider
+	parent    Element
+	slots     map[string][]Element
+	slotNames []string
 }
 
 type MarkupProvider interface {
@@ -20,18 +32,146 @@ type MarkupProvider interface {
 }
 
 func (be *BaseElement) Append(children ...Element) {
-	be.Children = append(be.Children, children...)
+	be.Children = append(be.Children, be.adopt(children)...)
+}
+
+func (be *BaseElement) Prepend(children ...Element) {
+	be.Children = append(be.adopt(children), be.Children...)
+}
+
+// InsertBefore inserts newChild before refChild, or appends it if refChild is nil.
+// Returns false if refChild is not a child of this element
+func (be *BaseElement) InsertBefore(newChild, refChild Element) bool {
+	if refChild == nil {
+		be.Append(newChild)
+		return true
+	}
+	if index := slices.Index(be.Children, refChild); index >= 0 {
+		be.Children = slices.Insert(be.Children, index, be.adopt([]Element{newChild})...)
+		return true
+	}
+	return false
+}
+
+// RemoveChild removes child from this element.
+// Returns false if child is not a child of this element
+func (be *BaseElement) RemoveChild(child Element) bool {
+	if index := slices.Index(be.Children, child); index >= 0 {
+		be.Children = slices.Delete(be.Children, index, index+1)
+		if child.Parent() == be {
+			child.setParent(nil)
+		}
+		return true
+	}
+	return false
+}
+
+// ReplaceChild replaces oldChild with newChild.
+// Returns false if oldChild is not a child of this element
+func (be *BaseElement) ReplaceChild(newChild, oldChild Element) bool {
+	if index := slices.Index(be.Children, oldChild); index >= 0 {
+		if newChild == nil {
+			return be.RemoveChild(oldChild)
+		}
+		be.Children[index] = newChild
+		newChild.setParent(be)
+		if oldChild.Parent() == be {
+			oldChild.setParent(nil)
+		}
+		return true
+	}
+	return false
+}
+
+// Parent returns the element this element was last appended to (elements are not
+// moved when appended to another element and would be written in both places).
+// For components that's their BaseElement, not the component element itself
+func (be *BaseElement) Parent() Element {
+	return be.parent
+}
+
+func (be *BaseElement) setParent(parent Element) {
+	be.parent = parent
+}
+
+// adopt skips nil elements and sets parent on the rest
+func (be *BaseElement) adopt(children []Element) []Element {
+	adopted := make([]Element, 0, len(children))
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		child.setParent(be)
+		adopted = append(adopted, child)
+	}
+	return adopted
 }
 
 func (be *BaseElement) HasChildren() bool {
 	return len(be.Children) > 0
 }
 
+// Clone returns a deep copy of the element and its children. Components
+// override Clone to return their type and copy their own state. Components
+// in the clone of a page, fragment or shadow host use the cloned Registrar,
+// elements cloned on their own keep the Registrar they were created with
+func (be *BaseElement) Clone() Element {
+	return be.cloneBase()
+}
+
+func (be *BaseElement) cloneBase() *BaseElement {
+	clone := &BaseElement{}
+	be.copyTo(clone)
+	return clone
+}
+
+// copyTo deep copies attributes, classes and children into dst,
+// markup provider is shared, parent is not copied
+func (be *BaseElement) copyTo(dst *BaseElement) {
+	dst.TagName = be.TagName
+	dst.MarkupProvider = be.MarkupProvider
+	dst.Attributes = Attributes{
+		attributes: maps.Clone(be.Attributes.attributes),
+		names:      slices.Clone(be.Attributes.names),
+	}
+	dst.ClassList = ClassList{
+		classList: slices.Clone(be.ClassList.classList),
+	}
+	dst.Children = nil
+	for _, child := range be.Children {
+		dst.Append(child.Clone())
+	}
+	dst.slots, dst.slotNames = nil, nil
+	for _, name := range be.slotNames {
+		elements := make([]Element, 0, len(be.slots[name]))
+		for _, el := range be.slots[name] {
+			elements = append(elements, el.Clone())
+		}
+		dst.SetSlot(name, elements...)
+	}
+}
+
+// GetChildren returns content children followed by slots elements
+func (be *BaseElement) GetChildren() []Element {
+	if len(be.slotNames) == 0 {
+		return be.Children
+	}
+	children := slices.Clone(be.Children)
+	for _, name := range be.slotNames {
+		children = append(children, be.slots[name]...)
+	}
+	return children
+}
+
 func (be *BaseElement) Write(w io.Writer) error {
-	if be.MarkupProvider == nil {
+	mp := be.MarkupProvider
+	if omp := overrideMarkupProvider(be.TagName); omp != nil {
+		mp = omp
+	}
+	if mp == nil {
 		return be.WriteFragment(ContentToken, w)
 	}
-	mup, err := be.MarkupProvider.GetMarkup()
+	mup, err := mp.GetMarkup()
 	if err != nil {
 		return err
 	}
@@ -47,32 +187,69 @@ func (be *BaseElement) WriteFragment(t string, w io.Writer) error {
 			}
 		}
 	case AttributesToken:
-		// set class attribute
-		if be.Attributes.attributes == nil {
-			be.Attributes.attributes = make(map[string]string)
-		}
 		if len(be.ClassList.classList) > 0 {
-			be.Attributes.attributes[attr.Class] = be.ClassList.String()
+			if _, err := io.WriteString(w, formatAttribute(attr.Class, be.ClassList.String())); err != nil {
+				return err
+			}
+			if len(be.Attributes.names) > 0 {
+				if _, err := io.WriteString(w, " "); err != nil {
+					return err
+				}
+			}
 		}
 		if err := be.Attributes.Write(w); err != nil {
 			return err
 		}
 	default:
+		if name, ok := strings.CutPrefix(t, SlotTokenPrefix); ok {
+			for _, el := range be.slots[name] {
+				if err := el.Write(w); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
 		return ErrUnknownToken(t)
 	}
 	return nil
 }
 
+// SetSlot sets elements written in place of {{.Slot:name}} token in the markup,
+// replacing the elements previously set for that slot
+func (be *BaseElement) SetSlot(name string, elements ...Element) {
+	if be.slots == nil {
+		be.slots = make(map[string][]Element)
+	}
+	if _, ok := be.slots[name]; !ok {
+		be.slotNames = append(be.slotNames, name)
+	}
+	be.slots[name] = be.adopt(elements)
+}
+
+func (be *BaseElement) GetSlot(name string) []Element {
+	return be.slots[name]
+}
+
 func (be *BaseElement) SetId(id string) {
 	be.SetAttribute(attr.Id, id)
 }
 
+// SetAttribute sets class attribute value as the class list
+func (be *BaseElement) SetAttribute(name, val string) {
+	if name == attr.Class {
+		be.ClassList.classList = nil
+		be.AddClass(strings.Fields(val)...)
+		return
+	}
+	be.Attributes.SetAttribute(name, val)
+}
+
 func (be *BaseElement) GetTagName() atom.Atom {
 	return be.TagName
 }
 
 func (be *BaseElement) GetElementById(id string) Element {
-	for _, child := range be.Children {
+	for _, child := range be.GetChildren() {
 		if cid := child.GetAttribute(attr.Id); cid == id {
 			return child
 		}
@@ -85,7 +262,7 @@ func (be *BaseElement) GetElementById(id string) Element {
 
 func (be *BaseElement) GetElementsByTagName(tagName atom.Atom) []Element {
 	matches := make([]Element, 0)
-	for _, child := range be.Children {
+	for _, child := range be.GetChildren() {
 		if child.GetTagName() == tagName {
 			matches = append(matches, child)
 		}
@@ -103,7 +280,7 @@ func (be *BaseElement) GetFirstElementByTagName(tagName atom.Atom) Element {
 
 func (be *BaseElement) GetElementsByClassName(names ...string) []Element {
 	matches := make([]Element, 0)
-	for _, child := range be.Children {
+	for _, child := range be.GetChildren() {
 		if child.HasClass(names...) {
 			matches = append(matches, child)
 		}
@@ -112,6 +289,29 @@ func (be *BaseElement) GetElementsByClassName(names ...string) []Element {
 	return matches
 }
 
+// QuerySelector returns the first descendant matching the selector
+// or nil, if there are no matches or the selector is not valid
+func (be *BaseElement) QuerySelector(selector string) Element {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		return nil
+	}
+	if matches := s.query([]selectorNode{{element: be}}, be.GetChildren(), true); len(matches) > 0 {
+		return matches[0]
+	}
+	return nil
+}
+
+// QuerySelectorAll returns all descendants matching the selector in document order.
+// See ParseSelector for the supported selectors
+func (be *BaseElement) QuerySelectorAll(selector string) []Element {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		return nil
+	}
+	return s.query([]selectorNode{{element: be}}, be.GetChildren(), false)
+}
+
 func NewElement(a atom.Atom, mp MarkupProvider) *BaseElement {
 	return &BaseElement{
 		TagName:        a,
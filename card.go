@@ -13,6 +13,17 @@ type CardElement struct {
 	r Registrar
 }
 
+func (ce *CardElement) Clone() Element {
+	return &CardElement{
+		BaseElement: ce.BaseElement.cloneBase(),
+		r:           ce.r,
+	}
+}
+
+func (ce *CardElement) bindRegistrar(registrars map[Registrar]Registrar) {
+	ce.r = boundRegistrar(ce.r, registrars)
+}
+
 func (ce *CardElement) AppendPoster(background, placeholder, poster string, hydrated bool) *CardElement {
 	if posterPlaceholder := ce.GetFirstElementByTagName(compton_atoms.Placeholder); posterPlaceholder != nil {
 		if hydrated {
@@ -34,7 +45,7 @@ func (ce *CardElement) AppendTitle(title string) *CardElement {
 }
 
 func (ce *CardElement) AppendProperty(title string, values ...Element) *CardElement {
-	if ul := ce.GetFirstElementByTagName(atom.Ul); ul != nil {
+	if ul := ce.QuerySelector("card > ul"); ul != nil {
 		liProperty := Li()
 		liProperty.AddClass("property")
 		spanTitle := SpanText(title)
@@ -49,8 +60,8 @@ func (ce *CardElement) AppendProperty(title string, values ...Element) *CardElem
 }
 
 func (ce *CardElement) AppendLabels(labels ...Element) *CardElement {
-	if liLables := ce.GetElementsByClassName("labels"); len(liLables) > 0 {
-		liLables[0].Append(labels...)
+	if liLabels := ce.QuerySelector("ul > li.labels"); liLabels != nil {
+		liLabels.Append(labels...)
 	}
 	return ce
 }
@@ -1,36 +1,33 @@
 package compton
 
 import (
-	"maps"
 	"slices"
 	"strings"
 )
 
+// ClassList preserves insertion order of class names,
+// so that two writes of the same element are identical
 type ClassList struct {
-	classList map[string]any
+	classList []string
 }
 
 func (cl *ClassList) AddClass(classes ...string) {
-	if cl.classList == nil {
-		cl.classList = make(map[string]any)
-	}
 	for _, class := range classes {
-		cl.classList[class] = nil
+		if class != "" && !slices.Contains(cl.classList, class) {
+			cl.classList = append(cl.classList, class)
+		}
 	}
 }
 
 func (cl *ClassList) RemoveClass(classes ...string) {
-	if cl.classList == nil {
-		cl.classList = make(map[string]any)
-	}
-	for _, class := range classes {
-		delete(cl.classList, class)
-	}
+	cl.classList = slices.DeleteFunc(cl.classList, func(class string) bool {
+		return slices.Contains(classes, class)
+	})
 }
 
 func (cl *ClassList) HasClass(classes ...string) bool {
 	for _, class := range classes {
-		if _, ok := cl.classList[class]; !ok {
+		if class != "" && !slices.Contains(cl.classList, class) {
 			return false
 		}
 	}
@@ -48,5 +45,18 @@ func (cl *ClassList) ToggleClass(classes ...string) {
 }
 
 func (cl *ClassList) String() string {
-	return strings.Join(slices.Collect(maps.Keys(cl.classList)), " ")
+	return strings.Join(cl.classList, " ")
+}
+
+func (cl *ClassList) GetClassNames() []string {
+	return slices.Clone(cl.classList)
+}
+
+// collectClassNames returns class names of the element and all its descendants
+func collectClassNames(el Element) []string {
+	classNames := el.GetClassNames()
+	for _, child := range el.GetChildren() {
+		classNames = append(classNames, collectClassNames(child)...)
+	}
+	return classNames
 }
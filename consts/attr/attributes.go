@@ -1,23 +1,29 @@
 package attr
 
 const (
-	Id      = "id"
-	Class   = "class"
-	Href    = "href"
-	Src     = "src"
-	For     = "for"
-	Type    = "type"
-	Value   = "value"
-	Label   = "label"
-	List    = "list"
-	Action  = "action"
-	Method  = "method"
-	Loading = "loading"
-	Rel     = "rel"
-	Charset = "charset"
-	Name    = "name"
-	Content = "content"
-	Target  = "target"
+	Id        = "id"
+	Class     = "class"
+	Href      = "href"
+	Src       = "src"
+	For       = "for"
+	Type      = "type"
+	Value     = "value"
+	Label     = "label"
+	List      = "list"
+	Action    = "action"
+	Method    = "method"
+	Loading   = "loading"
+	Rel       = "rel"
+	Charset   = "charset"
+	Name      = "name"
+	Content   = "content"
+	Target    = "target"
+	DataSrc   = "data-src"
+	Poster    = "poster"
+	Integrity = "integrity"
+	Nonce     = "nonce"
+	Property  = "property"
+	Media     = "media"
 )
 
 const (
@@ -35,6 +41,9 @@ const (
 	Viewport                        = "viewport"
 	ColorScheme                     = "color-scheme"
 	FormatDetection                 = "format-detection"
+	Description                     = "description"
+	Robots                          = "robots"
+	ThemeColor                      = "theme-color"
 
 	// meta content
 	BlackTranslucent = "black-translucent"
@@ -42,9 +51,19 @@ const (
 	DarkLight        = "dark light"
 	TelephoneNo      = "telephone=no"
 
+	// meta robots content
+	Index     = "index"
+	NoIndex   = "noindex"
+	Follow    = "follow"
+	NoFollow  = "nofollow"
+	NoArchive = "noarchive"
+	NoSnippet = "nosnippet"
+
 	// link rel
-	Manifest = "manifest"
-	Icon     = "icon"
+	Manifest   = "manifest"
+	Icon       = "icon"
+	Stylesheet = "stylesheet"
+	Canonical  = "canonical"
 
 	// link href
 	ManifestJson = "manifest.json"
@@ -52,4 +71,7 @@ const (
 
 	// link type
 	ImagePng = "image/png"
+
+	// script type
+	ApplicationLdJson = "application/ld+json"
 )
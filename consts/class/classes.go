@@ -6,10 +6,9 @@ import (
 	"github.com/boggydigital/compton/consts/direction"
 	"github.com/boggydigital/compton/consts/font_weight"
 	"github.com/boggydigital/compton/consts/size"
-	"maps"
+	"slices"
 	"strconv"
 	"strings"
-	"sync"
 )
 
 const (
@@ -40,16 +39,8 @@ const (
 	borderRadiusPfx     = "br"
 )
 
-var setClasses = make(map[string]any)
-var mtx = sync.Mutex{}
-
 func joinClassName(parts ...string) string {
-	mtx.Lock()
-	defer mtx.Unlock()
-
-	cn := strings.Join(parts, classNameSep)
-	setClasses[cn] = nil
-	return cn
+	return strings.Join(parts, classNameSep)
 }
 
 func classSelector(className string) string {
@@ -78,6 +69,27 @@ func classNameToHex(cn string) string {
 	return strings.Replace(cn, "_", "#", 1)
 }
 
+// isHexClassName checks for the hex color class name suffix: underscore
+// followed by 3, 4, 6 or 8 hex digits. Class names can come from the user
+// data (e.g. labels titles), other values would be written into the style
+func isHexClassName(cn string) bool {
+	digits, ok := strings.CutPrefix(cn, "_")
+	if !ok {
+		return false
+	}
+	switch len(digits) {
+	case 3, 4, 6, 8:
+	default:
+		return false
+	}
+	for _, c := range digits {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
 func RowGap(s size.Size) string {
 	return joinClassName(rowGapPfx, s.String())
 }
@@ -174,27 +186,44 @@ func PaddingBlock(s size.Size) string { return joinClassName(paddingBlockPfx, s.
 
 func BorderRadius(s size.Size) string { return joinClassName(borderRadiusPfx, s.String()) }
 
-func StyleClasses() []byte {
-	mtx.Lock()
-	defer mtx.Unlock()
+// StyleClassesFor generates style only for the utility classes among classNames,
+// other class names are ignored. Pages and other containers collect class names
+// of their elements when written, so that only the utility classes in use are written
+func StyleClassesFor(classNames ...string) []byte {
+	utilityClasses := make([]string, 0, len(classNames))
+	for _, className := range classNames {
+		if slices.Contains(utilityClasses, className) {
+			continue
+		}
+		if _, _, ok := parsePropertyValue(className); ok {
+			utilityClasses = append(utilityClasses, className)
+		}
+	}
+	// sorted to produce the same output for the same set of classes
+	slices.Sort(utilityClasses)
+
+	return styleClasses(utilityClasses)
+}
 
+func styleClasses(classNames []string) []byte {
 	sb := &strings.Builder{}
-	for className := range maps.Keys(setClasses) {
-		property, value := parsePropertyValue(className)
+	for _, className := range classNames {
+		property, value, _ := parsePropertyValue(className)
 		sb.WriteString(classSelector(className) + "{")
 		sb.WriteString(property + ":" + value + "}")
 	}
 	return []byte(sb.String())
 }
 
-func parsePropertyValue(className string) (string, string) {
+// parsePropertyValue returns custom property and value for the utility
+// class name, ok is false for class names that are not utility classes
+func parsePropertyValue(className string) (property, value string, ok bool) {
 	abbrParts := strings.Split(className, classNameSep)
 	if len(abbrParts) != 2 {
-		return "", ""
+		return "", "", false
 	}
 	pfx, sfx := abbrParts[0], abbrParts[1]
-	property := customProperty(pfx)
-	value := ""
+	property = customProperty(pfx)
 
 	switch pfx {
 	case alignContentPfx:
@@ -206,11 +235,13 @@ func parsePropertyValue(className string) (string, string) {
 	case justifyItemsPfx:
 		fallthrough
 	case textAlignPfx:
-		al := align.Parse(sfx)
-		value = al.String()
+		if al := align.Parse(sfx); al != align.Unset {
+			value = al.String()
+		}
 	case fontSizePfx:
-		sz := size.Parse(sfx)
-		value = sz.FontSizeCssValue()
+		if sz := size.Parse(sfx); sz != size.Unset {
+			value = sz.FontSizeCssValue()
+		}
 	case paddingInlinePfx:
 		fallthrough
 	case paddingBlockPfx:
@@ -222,8 +253,9 @@ func parsePropertyValue(className string) (string, string) {
 	case columnGapPfx:
 		fallthrough
 	case rowGapPfx:
-		sz := size.Parse(sfx)
-		value = sz.SizeCssValue()
+		if sz := size.Parse(sfx); sz != size.Unset {
+			value = sz.SizeCssValue()
+		}
 	case gridTemplateRowsPfx:
 		fallthrough
 	case widthPfx:
@@ -231,8 +263,7 @@ func parsePropertyValue(className string) (string, string) {
 	case heightPfx:
 		if fv, err := parseFloat(sfx); err == nil {
 			value = strconv.FormatFloat(fv, 'f', -1, 64) + "px"
-		} else {
-			sz := size.Parse(sfx)
+		} else if sz := size.Parse(sfx); sz != size.Unset {
 			value = sz.SizeCssValue()
 		}
 	case aspectRatioPfx:
@@ -240,8 +271,9 @@ func parsePropertyValue(className string) (string, string) {
 			value = strconv.FormatFloat(fv, 'f', -1, 64)
 		}
 	case flexDirectionPfx:
-		dr := direction.Parse(sfx)
-		value = dr.String()
+		if dr := direction.Parse(sfx); dr != direction.Unset {
+			value = dr.String()
+		}
 	case markerColorPfx:
 		fallthrough
 	case foregroundColorPfx:
@@ -249,18 +281,16 @@ func parsePropertyValue(className string) (string, string) {
 	case backgroundColorPfx:
 		fallthrough
 	case outlineColorPfx:
-		if strings.Contains(sfx, "_") {
+		if isHexClassName(sfx) {
 			value = classNameToHex(sfx)
-		} else {
-			cl := color.Parse(sfx)
+		} else if cl := color.Parse(sfx); cl != color.Unset {
 			value = cl.CssValue()
 		}
 	case fontWeightPfx:
-		wt := font_weight.Parse(sfx)
-		value = wt.CssValue()
-	default:
-		panic("class support not implemented for " + pfx)
+		if wt := font_weight.Parse(sfx); wt != font_weight.Unknown {
+			value = wt.CssValue()
+		}
 	}
 
-	return property, value
+	return property, value, value != ""
 }
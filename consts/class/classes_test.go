@@ -0,0 +1,68 @@
+package class
+
+import (
+	"testing"
+)
+
+func TestParsePropertyValue(t *testing.T) {
+	tests := []struct {
+		className string
+		property  string
+		value     string
+		ok        bool
+	}{
+		{"fg-red", "--fg", "var(--c-red)", true},
+		{"bg-_fff", "--bg", "#fff", true},
+		{"bg-_ffff", "--bg", "#ffff", true},
+		{"fg-_a0B1c2", "--fg", "#a0B1c2", true},
+		{"oc-_a0b1c2d3", "--oc", "#a0b1c2d3", true},
+		{"cm-_123", "--cm", "#123", true},
+		{"fg-_", "", "", false},
+		{"fg-_ff", "", "", false},
+		{"fg-_fffff", "", "", false},
+		{"fg-_fffffffff", "", "", false},
+		{"fg-_ggg", "", "", false},
+		{"fg-_0}body{display:none", "", "", false},
+		{"bg-_fff}body{display:none", "", "", false},
+		{"fg-_fff;x:y", "", "", false},
+		{"fg-unknown", "", "", false},
+		{"plain", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.className, func(t *testing.T) {
+			property, value, ok := parsePropertyValue(tt.className)
+			if ok != tt.ok {
+				t.Fatalf("got ok %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if property != tt.property || value != tt.value {
+				t.Errorf("got %q: %q, want %q: %q", property, value, tt.property, tt.value)
+			}
+		})
+	}
+}
+
+func TestStyleClassesFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		classNames []string
+		want       string
+	}{
+		{"none", nil, ""},
+		{"not utility classes", []string{"title", "card"}, ""},
+		{"sorted", []string{"fg-_fff", "bg-_000"}, ".bg-_000{--bg:#000}.fg-_fff{--fg:#fff}"},
+		{"duplicates", []string{"bg-_000", "bg-_000"}, ".bg-_000{--bg:#000}"},
+		{"malicious", []string{"fg-_0}body{display:none", "bg-_000"}, ".bg-_000{--bg:#000}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(StyleClassesFor(tt.classNames...)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
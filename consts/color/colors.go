@@ -61,6 +61,60 @@ func (c Color) CssValue() string {
 	return "var(--c-" + c.String() + ")"
 }
 
+// values match style/colors.css, for contexts where CSS custom
+// properties are not available (e.g. web app manifest, theme-color)
+var lightValues = map[Color]string{
+	Black:       "black",
+	White:       "white",
+	Transparent: "transparent",
+	Red:         "rgb(215,0,21)",
+	Orange:      "rgb(201,52,0)",
+	Yellow:      "rgb(255,204,0)",
+	Green:       "rgb(0,125,27)",
+	Mint:        "rgb(12,129,123)",
+	Teal:        "rgb(0,130,153)",
+	Cyan:        "rgb(0,113,164)",
+	Blue:        "rgb(0,64,221)",
+	Indigo:      "rgb(54,52,163)",
+	Purple:      "rgb(173,68,171)",
+	Pink:        "rgb(211,15,69)",
+	Brown:       "rgb(127,101,69)",
+	Gray:        "rgb(105,105,105)",
+	Background:  "rgb(242,242,242)",
+	Foreground:  "rgb(28,28,28)",
+	Highlight:   "white",
+}
+
+var darkValues = map[Color]string{
+	Black:       "black",
+	White:       "white",
+	Transparent: "transparent",
+	Red:         "rgb(255,69,58)",
+	Orange:      "rgb(255,159,10)",
+	Yellow:      "rgb(255,214,10)",
+	Green:       "rgb(50,215,75)",
+	Mint:        "rgb(102,212,207)",
+	Teal:        "rgb(106,196,220)",
+	Cyan:        "rgb(90,200,245)",
+	Blue:        "rgb(10,132,255)",
+	Indigo:      "rgb(94,92,230)",
+	Purple:      "rgb(191,90,242)",
+	Pink:        "rgb(255,55,95)",
+	Brown:       "rgb(172,142,104)",
+	Gray:        "rgb(152,152,152)",
+	Background:  "rgb(28,28,28)",
+	Foreground:  "rgb(242,242,242)",
+	Highlight:   "black",
+}
+
+func (c Color) LightValue() string {
+	return lightValues[c]
+}
+
+func (c Color) DarkValue() string {
+	return darkValues[c]
+}
+
 func Parse(s string) Color {
 	for c, str := range colorStrings {
 		if s == str {
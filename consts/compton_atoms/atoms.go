@@ -1,8 +1,11 @@
 package compton_atoms
 
 import (
+	"errors"
+	"fmt"
 	"golang.org/x/net/html/atom"
 	"path"
+	"sync"
 )
 
 const (
@@ -27,6 +30,10 @@ const (
 	Frow
 	Card
 	Placeholder
+	ShadowHost
+	Fragment
+	Deferred
+	Suspense
 )
 
 var atomStrings = map[atom.Atom]string{
@@ -51,25 +58,115 @@ var atomStrings = map[atom.Atom]string{
 	Frow:                "frow",
 	Card:                "card",
 	Placeholder:         "placeholder",
+	ShadowHost:          "shadow-host",
+	Fragment:            "fragment",
+	Deferred:            "deferred",
+	Suspense:            "suspense",
+}
+
+// Registration describes custom atom for third-party components.
+// Markup, style and script names are optional and default
+// to the MarkupName, StyleName, ScriptName conventions
+type Registration struct {
+	TagName    string
+	MarkupName string
+	StyleName  string
+	ScriptName string
+}
+
+var ErrAtomRegistered = errors.New("atom is already registered")
+
+var (
+	registrations = make(map[atom.Atom]Registration)
+	nextAtom      atom.Atom
+	atomsMtx      = sync.RWMutex{}
+)
+
+// Register allocates a new custom atom for the tag name. Registering
+// a tag name that's already used by compton, another registration or
+// a standard HTML atom returns ErrAtomRegistered
+func Register(r Registration) (atom.Atom, error) {
+	if r.TagName == "" {
+		return 0, errors.New("custom atom requires a tag name")
+	}
+
+	atomsMtx.Lock()
+	defer atomsMtx.Unlock()
+
+	if atom.Lookup([]byte(r.TagName)) != 0 {
+		return 0, fmt.Errorf("%w: %s is a standard atom", ErrAtomRegistered, r.TagName)
+	}
+	for _, str := range atomStrings {
+		if str == r.TagName {
+			return 0, fmt.Errorf("%w: %s", ErrAtomRegistered, r.TagName)
+		}
+	}
+
+	if nextAtom == 0 {
+		for a := range atomStrings {
+			if a >= nextAtom {
+				nextAtom = a + 1
+			}
+		}
+	}
+
+	a := nextAtom
+	nextAtom++
+
+	atomStrings[a] = r.TagName
+	registrations[a] = r
+
+	return a, nil
 }
 
 func Atos(a atom.Atom) string {
+	atomsMtx.RLock()
+	defer atomsMtx.RUnlock()
+
 	if str, ok := atomStrings[a]; ok {
 		return str
-	} else if an := a.String(); an != "" {
-		return an
 	}
-	panic("no string for atom")
+	return a.String()
+}
+
+func registration(a atom.Atom) Registration {
+	atomsMtx.RLock()
+	defer atomsMtx.RUnlock()
+
+	return registrations[a]
 }
 
 func MarkupName(a atom.Atom) string {
+	if mn := registration(a).MarkupName; mn != "" {
+		return mn
+	}
 	return path.Join("markup", Atos(a)+".html")
 }
 
 func StyleName(a atom.Atom) string {
+	if sn := registration(a).StyleName; sn != "" {
+		return sn
+	}
 	return path.Join("style", Atos(a)+".css")
 }
 
 func ScriptName(a atom.Atom) string {
+	if sn := registration(a).ScriptName; sn != "" {
+		return sn
+	}
 	return path.Join("script", Atos(a)+".js")
 }
+
+// Stoa returns custom atom for the tag name, falling back to
+// the standard atom lookup (0 if the name is not known)
+func Stoa(s string) atom.Atom {
+	atomsMtx.RLock()
+	defer atomsMtx.RUnlock()
+
+	for a, str := range atomStrings {
+		if str == s {
+			return a
+		}
+	}
+	return atom.Lookup([]byte(s))
+}
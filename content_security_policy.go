@@ -0,0 +1,199 @@
+package compton
+
+import (
+	"crypto/rand"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	cspHeader           = "Content-Security-Policy"
+	cspReportOnlyHeader = "Content-Security-Policy-Report-Only"
+)
+
+const (
+	CspSelf         = "'self'"
+	CspNone         = "'none'"
+	CspData         = "data:"
+	CspHttps        = "https:"
+	CspUnsafeInline = "'unsafe-inline'"
+)
+
+const (
+	defaultSrc   = "default-src"
+	scriptSrc    = "script-src"
+	styleSrcElem = "style-src-elem"
+	styleSrcAttr = "style-src-attr"
+	imgSrc       = "img-src"
+	frameSrc     = "frame-src"
+	connectSrc   = "connect-src"
+	formAction   = "form-action"
+	reportUri    = "report-uri"
+)
+
+// directivesOrder is the order directives are written in the policy
+var directivesOrder = []string{
+	defaultSrc,
+	scriptSrc,
+	styleSrcElem,
+	styleSrcAttr,
+	imgSrc,
+	frameSrc,
+	connectSrc,
+	formAction,
+}
+
+// ContentSecurityPolicy builds page Content-Security-Policy. Page computes
+// sha256 hashes of every inline script and style element and adds them to
+// script-src and style-src-elem (or uses nonce, when set). Style attributes
+// are allowed with style-src-attr 'unsafe-inline' by default, since
+// components use style attributes (e.g. SetTint, SVG atlas)
+type ContentSecurityPolicy struct {
+	directives map[string][]string
+	nonce      string
+	reportOnly bool
+	reportUri  string
+}
+
+func (csp *ContentSecurityPolicy) clone() *ContentSecurityPolicy {
+	clone := *csp
+	clone.directives = make(map[string][]string, len(csp.directives))
+	for directive, sources := range csp.directives {
+		clone.directives[directive] = slices.Clone(sources)
+	}
+	return &clone
+}
+
+func (csp *ContentSecurityPolicy) add(directive string, sources ...string) *ContentSecurityPolicy {
+	csp.directives[directive] = append(csp.directives[directive], sources...)
+	return csp
+}
+
+func (csp *ContentSecurityPolicy) DefaultSrc(sources ...string) *ContentSecurityPolicy {
+	return csp.add(defaultSrc, sources...)
+}
+
+// ScriptSrc adds sources to script-src, in addition to inline scripts hashes or nonce
+func (csp *ContentSecurityPolicy) ScriptSrc(sources ...string) *ContentSecurityPolicy {
+	return csp.add(scriptSrc, sources...)
+}
+
+// StyleSrc adds sources to style-src-elem, in addition to inline styles hashes or nonce
+func (csp *ContentSecurityPolicy) StyleSrc(sources ...string) *ContentSecurityPolicy {
+	return csp.add(styleSrcElem, sources...)
+}
+
+// StyleSrcAttr replaces default style-src-attr 'unsafe-inline' sources
+func (csp *ContentSecurityPolicy) StyleSrcAttr(sources ...string) *ContentSecurityPolicy {
+	csp.directives[styleSrcAttr] = sources
+	return csp
+}
+
+func (csp *ContentSecurityPolicy) ImgSrc(sources ...string) *ContentSecurityPolicy {
+	return csp.add(imgSrc, sources...)
+}
+
+func (csp *ContentSecurityPolicy) FrameSrc(sources ...string) *ContentSecurityPolicy {
+	return csp.add(frameSrc, sources...)
+}
+
+func (csp *ContentSecurityPolicy) ConnectSrc(sources ...string) *ContentSecurityPolicy {
+	return csp.add(connectSrc, sources...)
+}
+
+func (csp *ContentSecurityPolicy) FormAction(sources ...string) *ContentSecurityPolicy {
+	return csp.add(formAction, sources...)
+}
+
+// Nonce sets per-request nonce, that is used instead of inline scripts and styles hashes.
+// Nonce is set as an attribute on every script, style and stylesheet link
+func (csp *ContentSecurityPolicy) Nonce(nonce string) *ContentSecurityPolicy {
+	csp.nonce = nonce
+	return csp
+}
+
+// ReportOnly sends policy with Content-Security-Policy-Report-Only header,
+// violations are reported, but not enforced
+func (csp *ContentSecurityPolicy) ReportOnly(reportOnly bool) *ContentSecurityPolicy {
+	csp.reportOnly = reportOnly
+	return csp
+}
+
+func (csp *ContentSecurityPolicy) ReportUri(uri string) *ContentSecurityPolicy {
+	csp.reportUri = uri
+	return csp
+}
+
+func (csp *ContentSecurityPolicy) HeaderName() string {
+	if csp.reportOnly {
+		return cspReportOnlyHeader
+	}
+	return cspHeader
+}
+
+// build creates policy with inline scripts and styles sources
+// (hashes) and external sources (e.g. AssetStore)
+func (csp *ContentSecurityPolicy) build(scriptSources, styleSources []string) string {
+
+	directives := maps.Clone(csp.directives)
+
+	if csp.nonce != "" {
+		nonceSource := "'nonce-" + csp.nonce + "'"
+		if len(scriptSources) > 0 {
+			scriptSources = []string{nonceSource}
+		}
+		if len(styleSources) > 0 {
+			styleSources = []string{nonceSource}
+		}
+	}
+
+	if len(scriptSources) > 0 {
+		directives[scriptSrc] = append(scriptSources, directives[scriptSrc]...)
+	}
+	if len(styleSources) > 0 {
+		directives[styleSrcElem] = append(styleSources, directives[styleSrcElem]...)
+	}
+	if _, ok := directives[styleSrcAttr]; !ok && len(directives) > 0 {
+		directives[styleSrcAttr] = []string{CspUnsafeInline}
+	}
+
+	policy := make([]string, 0, len(directivesOrder)+1)
+	for _, directive := range directivesOrder {
+		if sources, ok := directives[directive]; ok && len(sources) > 0 {
+			policy = append(policy, directive+" "+strings.Join(sources, " "))
+		}
+	}
+	if csp.reportUri != "" && len(policy) > 0 {
+		policy = append(policy, reportUri+" "+csp.reportUri)
+	}
+
+	return strings.Join(policy, "; ")
+}
+
+// uniqueSources removes duplicate sources (e.g. hashes of the same scripts),
+// preserving order
+func uniqueSources(sources []string) []string {
+	unique := make([]string, 0, len(sources))
+	for _, source := range sources {
+		if !slices.Contains(unique, source) {
+			unique = append(unique, source)
+		}
+	}
+	return unique
+}
+
+func NewContentSecurityPolicy() *ContentSecurityPolicy {
+	return &ContentSecurityPolicy{
+		directives: make(map[string][]string),
+	}
+}
+
+// NewNonce returns random base64 value suitable for ContentSecurityPolicy.Nonce
+func NewNonce() (string, error) {
+	bts := make([]byte, 16)
+	if _, err := rand.Read(bts); err != nil {
+		return "", err
+	}
+	return b64.EncodeToString(bts), nil
+}
@@ -0,0 +1,221 @@
+package compton
+
+import (
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestContentSecurityPolicyBuild(t *testing.T) {
+	tests := []struct {
+		name          string
+		csp           *ContentSecurityPolicy
+		scriptSources []string
+		styleSources  []string
+		want          string
+	}{
+		{"empty", NewContentSecurityPolicy(), nil, nil, ""},
+		{"report uri only", NewContentSecurityPolicy().ReportUri("/csp"), nil, nil, ""},
+		{
+			"hashes",
+			NewContentSecurityPolicy(),
+			[]string{"'sha256-a'", "'sha256-b'"},
+			[]string{"'sha256-c'"},
+			"script-src 'sha256-a' 'sha256-b'; style-src-elem 'sha256-c'; style-src-attr 'unsafe-inline'",
+		},
+		{
+			"directives order",
+			NewContentSecurityPolicy().FormAction(CspSelf).ImgSrc(CspData, CspSelf).DefaultSrc(CspNone),
+			nil,
+			nil,
+			"default-src 'none'; style-src-attr 'unsafe-inline'; img-src data: 'self'; form-action 'self'",
+		},
+		{
+			"hashes precede sources",
+			NewContentSecurityPolicy().ScriptSrc(CspSelf).StyleSrc(CspHttps),
+			[]string{"'sha256-a'"},
+			[]string{"'sha256-b'"},
+			"script-src 'sha256-a' 'self'; style-src-elem 'sha256-b' https:; style-src-attr 'unsafe-inline'",
+		},
+		{
+			"nonce replaces hashes",
+			NewContentSecurityPolicy().Nonce("n"),
+			[]string{"'sha256-a'", "'sha256-b'"},
+			[]string{"'sha256-c'"},
+			"script-src 'nonce-n'; style-src-elem 'nonce-n'; style-src-attr 'unsafe-inline'",
+		},
+		{
+			"nonce without inline elements",
+			NewContentSecurityPolicy().Nonce("n").DefaultSrc(CspSelf),
+			nil,
+			nil,
+			"default-src 'self'; style-src-attr 'unsafe-inline'",
+		},
+		{
+			"style-src-attr",
+			NewContentSecurityPolicy().DefaultSrc(CspSelf).StyleSrcAttr(CspNone),
+			nil,
+			nil,
+			"default-src 'self'; style-src-attr 'none'",
+		},
+		{
+			"report uri",
+			NewContentSecurityPolicy().DefaultSrc(CspSelf).ReportUri("/csp"),
+			nil,
+			nil,
+			"default-src 'self'; style-src-attr 'unsafe-inline'; report-uri /csp",
+		},
+		{
+			"all directives",
+			NewContentSecurityPolicy().
+				DefaultSrc(CspNone).
+				ScriptSrc(CspSelf).
+				StyleSrc(CspSelf).
+				StyleSrcAttr(CspUnsafeInline).
+				ImgSrc(CspSelf).
+				FrameSrc(CspSelf).
+				ConnectSrc(CspSelf).
+				FormAction(CspSelf),
+			nil,
+			nil,
+			"default-src 'none'; script-src 'self'; style-src-elem 'self'; style-src-attr 'unsafe-inline'; " +
+				"img-src 'self'; frame-src 'self'; connect-src 'self'; form-action 'self'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.csp.build(tt.scriptSources, tt.styleSources); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentSecurityPolicyBuildDoesNotChangePolicy(t *testing.T) {
+	csp := NewContentSecurityPolicy().ScriptSrc(CspSelf)
+	first := csp.build([]string{"'sha256-a'"}, nil)
+	second := csp.build([]string{"'sha256-b'"}, nil)
+
+	if want := "script-src 'sha256-b' 'self'; style-src-attr 'unsafe-inline'"; second != want {
+		t.Errorf("got %q after %q, want %q", second, first, want)
+	}
+}
+
+func TestContentSecurityPolicyHeaderName(t *testing.T) {
+	tests := []struct {
+		reportOnly bool
+		want       string
+	}{
+		{false, "Content-Security-Policy"},
+		{true, "Content-Security-Policy-Report-Only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := NewContentSecurityPolicy().ReportOnly(tt.reportOnly).HeaderName(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueSources(t *testing.T) {
+	tests := []struct {
+		sources []string
+		want    []string
+	}{
+		{nil, []string{}},
+		{[]string{"a", "b"}, []string{"a", "b"}},
+		{[]string{"b", "a", "b", "a", "c"}, []string{"b", "a", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.sources, ","), func(t *testing.T) {
+			if got := uniqueSources(tt.sources); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNonce(t *testing.T) {
+	first, err := NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == "" || first == second {
+		t.Errorf("got nonces %q and %q, want different non-empty values", first, second)
+	}
+}
+
+func TestPageContentSecurityPolicy(t *testing.T) {
+	script := Script([]byte("console.log(1)"))
+
+	tests := []struct {
+		name     string
+		csp      *ContentSecurityPolicy
+		header   string
+		contains []string
+		excludes []string
+		body     string
+	}{
+		{
+			name:     "hashes",
+			csp:      NewContentSecurityPolicy().DefaultSrc(CspSelf),
+			header:   cspHeader,
+			contains: []string{"default-src 'self'", "'" + script.Sha256() + "'", "style-src-elem 'sha256-"},
+			excludes: []string{"'nonce-"},
+		},
+		{
+			name:     "nonce",
+			csp:      NewContentSecurityPolicy().DefaultSrc(CspSelf).Nonce("n0nce"),
+			header:   cspHeader,
+			contains: []string{"script-src 'nonce-n0nce'", "style-src-elem 'nonce-n0nce'"},
+			excludes: []string{"'sha256-"},
+			body:     "<script nonce='n0nce'>console.log(1)</script>",
+		},
+		{
+			name:     "report only",
+			csp:      NewContentSecurityPolicy().DefaultSrc(CspSelf).ReportOnly(true),
+			header:   cspReportOnlyHeader,
+			contains: []string{"default-src 'self'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Page("csp").SetContentSecurityPolicy(tt.csp)
+			p.Append(script.Clone())
+
+			rec := httptest.NewRecorder()
+			if err := p.WriteResponse(rec); err != nil {
+				t.Fatal(err)
+			}
+
+			policy := rec.Header().Get(tt.header)
+			if policy == "" {
+				t.Fatalf("no %s header", tt.header)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(policy, s) {
+					t.Errorf("policy %q doesn't contain %q", policy, s)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(policy, s) {
+					t.Errorf("policy %q contains %q", policy, s)
+				}
+			}
+
+			if body := rec.Body.String(); !strings.Contains(body, tt.body) {
+				t.Errorf("body doesn't contain %q", tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package compton
+
+import (
+	"github.com/boggydigital/compton/consts/compton_atoms"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// deferredElement is implemented by DeferredElement and the components
+// that embed it, for the page to start and resolve them
+type deferredElement interface {
+	start()
+	resolve()
+}
+
+// streamRegistrar is implemented by Registrars that stream the page (see PageElement.WriteStream)
+// and need to write styles, requirements and deferrals registered after they were written:
+// before and after the deferred element
+type streamRegistrar interface {
+	streamElements(el Element) ([]Element, []Element)
+}
+
+// DeferredElement is a placeholder for elements that are produced later,
+// by a function or sent over a channel. With PageElement.WriteStream the page
+// is flushed up to the DeferredElement, while the elements are produced.
+// Elements are appended as children, once received
+type DeferredElement struct {
+	*BaseElement
+	r        Registrar
+	produce  func() Element
+	elements <-chan Element
+	once     *sync.Once
+}
+
+// Clone doesn't wait for the elements: clone of the unresolved DeferredElement
+// runs its own producing function. DeferredChan clones share the channel,
+// so each element is received by one of them. Producing function is shared
+// as well: it registers with the Registrar it uses (Suspense producers
+// get the Registrar of the clone)
+func (de *DeferredElement) Clone() Element {
+	return &DeferredElement{
+		BaseElement: de.BaseElement.cloneBase(),
+		r:           de.r,
+		produce:     de.produce,
+		elements:    de.elements,
+		once:        &sync.Once{},
+	}
+}
+
+func (de *DeferredElement) bindRegistrar(registrars map[Registrar]Registrar) {
+	de.r = boundRegistrar(de.r, registrars)
+}
+
+// start runs producing function, once
+func (de *DeferredElement) start() {
+	de.once.Do(func() {
+		if de.produce == nil {
+			return
+		}
+		elements := make(chan Element, 1)
+		de.elements = elements
+		go func() {
+			defer close(elements)
+			elements <- de.produce()
+		}()
+	})
+}
+
+// resolve waits for all elements to be produced
+func (de *DeferredElement) resolve() {
+	de.start()
+	if de.elements == nil {
+		return
+	}
+	for el := range de.elements {
+		if el != nil {
+			de.BaseElement.Append(el)
+		}
+	}
+	de.done()
+}
+
+// done drops producing function and channel, once all elements
+// are received, so that clones write the received elements only
+func (de *DeferredElement) done() {
+	de.produce, de.elements = nil, nil
+}
+
+func (de *DeferredElement) Write(w io.Writer) error {
+	de.start()
+	for _, child := range de.Children {
+		if err := child.Write(w); err != nil {
+			return err
+		}
+	}
+
+	if de.elements == nil {
+		return nil
+	}
+
+	flush(w)
+
+	for el := range de.elements {
+		if el == nil {
+			continue
+		}
+		de.BaseElement.Append(el)
+		if err := writeReceived(w, de.r, el); err != nil {
+			return err
+		}
+	}
+	de.done()
+	return nil
+}
+
+// writeReceived writes element received by the deferred element, along with
+// the elements registered by its producer, while streaming, and flushes the writer
+func writeReceived(w io.Writer, r Registrar, el Element) error {
+	elements := []Element{el}
+	if sr, ok := r.(streamRegistrar); ok {
+		before, after := sr.streamElements(el)
+		elements = slices.Concat(before, elements, after)
+	}
+	for _, se := range elements {
+		if err := se.Write(w); err != nil {
+			return err
+		}
+	}
+	flush(w)
+	return nil
+}
+
+func flush(w io.Writer) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Deferred creates placeholder for the element produced by the function.
+// Producing function runs in its own goroutine, when the page is written.
+// Nil function (or nil element) produces nothing
+func Deferred(r Registrar, produce func() Element) *DeferredElement {
+	return &DeferredElement{
+		BaseElement: NewElement(contentMarkup(compton_atoms.Deferred)),
+		r:           r,
+		produce:     produce,
+		once:        &sync.Once{},
+	}
+}
+
+// DeferredChan creates placeholder for the elements sent over the channel,
+// elements are written in order received, until the channel is closed.
+// Nil channel produces nothing, nil elements are skipped
+func DeferredChan(r Registrar, elements <-chan Element) *DeferredElement {
+	return &DeferredElement{
+		BaseElement: NewElement(contentMarkup(compton_atoms.Deferred)),
+		r:           r,
+		elements:    elements,
+		once:        &sync.Once{},
+	}
+}
+
+// flushElement flushes the writer when written, to send
+// everything written before it to the client
+type flushElement struct {
+	*BaseElement
+	flushed func()
+}
+
+func (fe *flushElement) Write(w io.Writer) error {
+	flush(w)
+	if fe.flushed != nil {
+		fe.flushed()
+	}
+	return nil
+}
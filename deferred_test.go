@@ -0,0 +1,82 @@
+package compton
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeferredNilElements(t *testing.T) {
+	tests := []struct {
+		name     string
+		deferred func(p PageElement) Element
+	}{
+		{"nil producer", func(p PageElement) Element {
+			return Deferred(p, nil)
+		}},
+		{"nil element", func(p PageElement) Element {
+			return Deferred(p, func() Element { return nil })
+		}},
+		{"nil channel", func(p PageElement) Element {
+			return DeferredChan(p, nil)
+		}},
+		{"nil channel element", func(p PageElement) Element {
+			elements := make(chan Element, 2)
+			elements <- nil
+			elements <- Text("sent")
+			close(elements)
+			return DeferredChan(p, elements)
+		}},
+		{"nil suspense element", func(p PageElement) Element {
+			return Suspense(p, "nil", Text("..."), func(ctx context.Context, r Registrar) Element { return nil })
+		}},
+	}
+
+	writes := []struct {
+		name  string
+		write func(p PageElement) error
+	}{
+		{"write", func(p PageElement) error { return p.Write(new(bytes.Buffer)) }},
+		{"stream", func(p PageElement) error { return p.WriteStream(httptest.NewRecorder()) }},
+	}
+
+	for _, tt := range tests {
+		for _, wt := range writes {
+			t.Run(tt.name+" "+wt.name, func(t *testing.T) {
+				p := Page("deferred")
+				p.Append(tt.deferred(p))
+				if err := wt.write(p); err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+	}
+}
+
+func TestDeferredProducersRunConcurrently(t *testing.T) {
+	const delay = 100 * time.Millisecond
+
+	p := Page("deferred")
+	for _, content := range []string{"one", "two", "three"} {
+		p.Append(Deferred(p, func() Element {
+			time.Sleep(delay)
+			return Text(content)
+		}))
+	}
+
+	start := time.Now()
+	buf := new(bytes.Buffer)
+	if err := p.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed >= 2*delay {
+		t.Errorf("got %v for three %v producers, want less than %v", elapsed, delay, 2*delay)
+	}
+	if !strings.Contains(buf.String(), "onetwothree") {
+		t.Error("produced elements are not written in order")
+	}
+}
@@ -19,10 +19,41 @@ type DetailsSummaryElement struct {
 	details Element
 }
 
+func (dse *DetailsSummaryElement) Clone() Element {
+	clone := &DetailsSummaryElement{}
+	dse.BaseElement.copyTo(&clone.BaseElement)
+	clone.details = clone.Children[0]
+	return clone
+}
+
 func (dse *DetailsSummaryElement) Append(children ...Element) {
 	dse.details.Append(children...)
 }
 
+// Prepend inserts children after the summary, that needs to remain the first details child
+func (dse *DetailsSummaryElement) Prepend(children ...Element) {
+	if content := dse.details.GetChildren(); len(content) > 1 {
+		refChild := content[1]
+		for _, child := range children {
+			dse.details.InsertBefore(child, refChild)
+		}
+	} else {
+		dse.details.Append(children...)
+	}
+}
+
+func (dse *DetailsSummaryElement) InsertBefore(newChild, refChild Element) bool {
+	return dse.details.InsertBefore(newChild, refChild)
+}
+
+func (dse *DetailsSummaryElement) RemoveChild(child Element) bool {
+	return dse.details.RemoveChild(child)
+}
+
+func (dse *DetailsSummaryElement) ReplaceChild(newChild, oldChild Element) bool {
+	return dse.details.ReplaceChild(newChild, oldChild)
+}
+
 func (dse *DetailsSummaryElement) AppendSummary(children ...Element) {
 	if summary := dse.getSummary(); summary != nil {
 		summary.Append(children...)
@@ -97,6 +128,9 @@ func (dse *DetailsSummaryElement) getSummary() Element {
 }
 
 func (dse *DetailsSummaryElement) Write(w io.Writer) error {
+	if ok, err := writeOverride(&dse.BaseElement, w); ok {
+		return err
+	}
 	return dse.details.Write(w)
 }
 
@@ -115,6 +149,7 @@ func create(r Registrar, summary Element, open bool) *DetailsSummaryElement {
 		},
 		details: Details(),
 	}
+	dse.BaseElement.Append(dse.details)
 
 	if open {
 		dse.details.SetAttribute("open", "")
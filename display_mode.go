@@ -0,0 +1,12 @@
+package compton
+
+// DisplayMode is the web app manifest display mode
+// (https://developer.mozilla.org/en-US/docs/Web/Manifest/display)
+type DisplayMode string
+
+const (
+	DisplayFullscreen DisplayMode = "fullscreen"
+	DisplayStandalone DisplayMode = "standalone"
+	DisplayMinimalUi  DisplayMode = "minimal-ui"
+	DisplayBrowser    DisplayMode = "browser"
+)
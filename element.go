@@ -7,10 +7,23 @@ import (
 
 type Element interface {
 	Append(children ...Element)
+	Prepend(children ...Element)
+	InsertBefore(newChild, refChild Element) bool
+	RemoveChild(child Element) bool
+	ReplaceChild(newChild, oldChild Element) bool
 	HasChildren() bool
+	GetChildren() []Element
+
+	SetSlot(name string, elements ...Element)
+	GetSlot(name string) []Element
+
+	Parent() Element
+	setParent(parent Element)
 
 	Write(w io.Writer) error
 
+	Clone() Element
+
 	GetTagName() atom.Atom
 
 	SetId(id string)
@@ -19,12 +32,18 @@ type Element interface {
 	RemoveClass(names ...string)
 	HasClass(names ...string) bool
 	ToggleClass(names ...string)
+	GetClassNames() []string
 
 	SetAttribute(name, val string)
 	GetAttribute(name string) string
+	HasAttribute(name string) bool
+	RemoveAttribute(name string)
 
 	GetElementById(id string) Element
 	GetElementsByTagName(tagName atom.Atom) []Element
 	GetFirstElementByTagName(tagName atom.Atom) Element
 	GetElementsByClassName(names ...string) []Element
+
+	QuerySelector(selector string) Element
+	QuerySelectorAll(selector string) []Element
 }
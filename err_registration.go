@@ -0,0 +1,20 @@
+package compton
+
+import "fmt"
+
+func ErrRegistration(name string, err error) error {
+	return fmt.Errorf("registering %s: %w", name, err)
+}
+
+// errorRegistrar is implemented by Registrars that collect registration errors
+// of the components that register with other Registrars (e.g. ShadowHostElement)
+type errorRegistrar interface {
+	registrationError(err error)
+}
+
+// reportRegistrationError reports error to the Registrar, if it collects errors
+func reportRegistrationError(r Registrar, err error) {
+	if er, ok := r.(errorRegistrar); ok {
+		er.registrationError(err)
+	}
+}
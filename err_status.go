@@ -0,0 +1,71 @@
+package compton
+
+import (
+	"errors"
+	"net/http"
+)
+
+// StatusError is an error with the HTTP status code, that pages with that
+// error are written with (see PageElement.Error and ErrorPage)
+type StatusError struct {
+	StatusCode int
+	Err        error
+}
+
+func (se *StatusError) Error() string {
+	if se.Err != nil {
+		return se.Err.Error()
+	}
+	return http.StatusText(se.StatusCode)
+}
+
+func (se *StatusError) Unwrap() error {
+	return se.Err
+}
+
+func ErrStatus(statusCode int, err error) error {
+	return &StatusError{StatusCode: statusCode, Err: err}
+}
+
+func ErrNotFound(err error) error {
+	return ErrStatus(http.StatusNotFound, err)
+}
+
+func ErrForbidden(err error) error {
+	return ErrStatus(http.StatusForbidden, err)
+}
+
+func ErrBadRequest(err error) error {
+	return ErrStatus(http.StatusBadRequest, err)
+}
+
+// StatusCode returns HTTP status code for the error: StatusError code,
+// http.StatusOK for nil error and http.StatusInternalServerError otherwise
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode
+	}
+	return http.StatusInternalServerError
+}
+
+// errorMessage returns message that is safe to show: messages of client errors
+// or the status text for server errors. Development mode shows all messages
+func errorMessage(err error, development bool) string {
+	statusCode := StatusCode(err)
+	se, ok := err.(*StatusError)
+	switch {
+	case ok && se.Err == nil:
+		// error message is the status text
+		return http.StatusText(statusCode)
+	case development:
+		return http.StatusText(statusCode) + ": " + err.Error()
+	case statusCode < http.StatusInternalServerError:
+		return err.Error()
+	default:
+		return http.StatusText(statusCode)
+	}
+}
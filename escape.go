@@ -0,0 +1,90 @@
+package compton
+
+import (
+	"github.com/boggydigital/compton/consts/attr"
+	"html"
+	"strings"
+)
+
+// unsafeUrlReplacement is the value written instead of a URL attribute value
+// with a scheme that can execute code (same as html/template uses)
+const unsafeUrlReplacement = "#ZgotmplZ"
+
+var urlAttributes = map[string]any{
+	attr.Href:    nil,
+	attr.Src:     nil,
+	attr.Action:  nil,
+	attr.DataSrc: nil,
+	attr.Poster:  nil,
+	"formaction": nil,
+	"cite":       nil,
+	"xlink:href": nil,
+}
+
+var safeUrlSchemes = []string{
+	"http:",
+	"https:",
+	"mailto:",
+	"tel:",
+	"data:image/",
+}
+
+func isUrlAttribute(name string) bool {
+	_, ok := urlAttributes[strings.ToLower(name)]
+	return ok
+}
+
+// filterUrl returns the url if it's relative or uses one of the safe schemes
+// and unsafeUrlReplacement otherwise (e.g. for javascript: URLs)
+func filterUrl(url string) string {
+	trimmed := strings.ToLower(strings.TrimSpace(url))
+	colon := strings.IndexByte(trimmed, ':')
+	if colon < 0 {
+		return url
+	}
+	// a colon after a path, query or fragment separator is not a scheme
+	if sep := strings.IndexAny(trimmed, "/?#"); sep >= 0 && sep < colon {
+		return url
+	}
+	for _, scheme := range safeUrlSchemes {
+		if strings.HasPrefix(trimmed, scheme) {
+			return url
+		}
+	}
+	return unsafeUrlReplacement
+}
+
+// escapeText escapes text node content
+func escapeText(s string) string {
+	return html.EscapeString(s)
+}
+
+// escapeAttributeValue escapes quoted attribute value, filtering URL
+// attribute values that use unsafe schemes
+func escapeAttributeValue(name, val string) string {
+	if isUrlAttribute(name) {
+		val = filterUrl(val)
+	}
+	return html.EscapeString(val)
+}
+
+// escapeRawText prevents raw text element (style, script) content
+// from closing the element early
+func escapeRawText(tag, s string) string {
+	closing := "</" + tag
+	if !strings.Contains(strings.ToLower(s), closing) {
+		return s
+	}
+	sb := strings.Builder{}
+	for ii := 0; ii < len(s); {
+		if ii+len(closing) <= len(s) && strings.EqualFold(s[ii:ii+len(closing)], closing) {
+			sb.WriteString(`<\/`)
+			sb.WriteString(s[ii+2 : ii+len(closing)])
+			ii += len(closing)
+		} else {
+			sb.WriteByte(s[ii])
+			ii++
+		}
+	}
+	return sb.String()
+}
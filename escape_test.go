@@ -0,0 +1,145 @@
+package compton
+
+import (
+	"bytes"
+	"golang.org/x/net/html/atom"
+	"testing"
+)
+
+func TestFilterUrl(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"", ""},
+		{"/path", "/path"},
+		{"relative/path?q=1#f", "relative/path?q=1#f"},
+		{"a/b:c", "a/b:c"},
+		{"?q=a:b", "?q=a:b"},
+		{"#a:b", "#a:b"},
+		{"http://example.com", "http://example.com"},
+		{"https://example.com", "https://example.com"},
+		{"HTTPS://example.com", "HTTPS://example.com"},
+		{"mailto:user@example.com", "mailto:user@example.com"},
+		{"tel:+1234567890", "tel:+1234567890"},
+		{"data:image/png;base64,AAAA", "data:image/png;base64,AAAA"},
+		{"data:text/html;base64,AAAA", unsafeUrlReplacement},
+		{"javascript:alert(1)", unsafeUrlReplacement},
+		{"JavaScript:alert(1)", unsafeUrlReplacement},
+		{" javascript:alert(1)", unsafeUrlReplacement},
+		{"vbscript:msgbox(1)", unsafeUrlReplacement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := filterUrl(tt.url); got != tt.want {
+				t.Errorf("filterUrl(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"", ""},
+		{"text", "text"},
+		{"<b>bold</b>", "&lt;b&gt;bold&lt;/b&gt;"},
+		{"a & b", "a &amp; b"},
+		{`"quoted" 'single'`, "&#34;quoted&#34; &#39;single&#39;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := escapeText(tt.s); got != tt.want {
+				t.Errorf("escapeText(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeAttributeValue(t *testing.T) {
+	tests := []struct {
+		name string
+		val  string
+		want string
+	}{
+		{"class", "a b", "a b"},
+		{"title", `"><script>`, "&#34;&gt;&lt;script&gt;"},
+		{"title", "javascript:alert(1)", "javascript:alert(1)"},
+		{"href", "javascript:alert(1)", unsafeUrlReplacement},
+		{"HREF", "javascript:alert(1)", unsafeUrlReplacement},
+		{"src", "javascript:alert(1)", unsafeUrlReplacement},
+		{"action", "javascript:alert(1)", unsafeUrlReplacement},
+		{"data-src", "javascript:alert(1)", unsafeUrlReplacement},
+		{"poster", "javascript:alert(1)", unsafeUrlReplacement},
+		{"formaction", "javascript:alert(1)", unsafeUrlReplacement},
+		{"cite", "javascript:alert(1)", unsafeUrlReplacement},
+		{"xlink:href", "javascript:alert(1)", unsafeUrlReplacement},
+		{"href", "/path?a=1&b=2", "/path?a=1&amp;b=2"},
+		{"href", "https://example.com", "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"="+tt.val, func(t *testing.T) {
+			if got := escapeAttributeValue(tt.name, tt.val); got != tt.want {
+				t.Errorf("escapeAttributeValue(%q, %q) = %q, want %q", tt.name, tt.val, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeRawText(t *testing.T) {
+	tests := []struct {
+		tag  string
+		s    string
+		want string
+	}{
+		{"script", "", ""},
+		{"script", "let a = 1 < 2;", "let a = 1 < 2;"},
+		{"script", `let s = "</script>";`, `let s = "<\/script>";`},
+		{"script", `"</SCRIPT>"`, `"<\/SCRIPT>"`},
+		{"script", "</script></script>", `<\/script><\/script>`},
+		{"script", "</style>", "</style>"},
+		{"style", "a::after{content:'</style>'}", `a::after{content:'<\/style>'}`},
+		{"style", "</Style", `<\/Style`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag+":"+tt.s, func(t *testing.T) {
+			if got := escapeRawText(tt.tag, tt.s); got != tt.want {
+				t.Errorf("escapeRawText(%q, %q) = %q, want %q", tt.tag, tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestElementsEscaping(t *testing.T) {
+	link := AtomicElement(atom.A)
+	link.SetAttribute("href", "javascript:alert(1)")
+	link.SetAttribute("title", `"quoted"`)
+
+	tests := []struct {
+		name string
+		el   Element
+		want string
+	}{
+		{"text", Text("<b>&</b>"), "&lt;b&gt;&amp;&lt;/b&gt;"},
+		{"link", link, `<a href='#ZgotmplZ' title='&#34;quoted&#34;'></a>`},
+		{"script", Script([]byte(`"</script>"`)), `<script >"<\/script>"</script>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := tt.el.Write(buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package compton
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/boggydigital/compton/consts/attr"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	exportAssetsDir   = "assets"
+	exportNotFound    = "404.html"
+	exportSitemap     = "sitemap.xml"
+	sitemapXmlns      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	exportDirFileMode = 0755
+)
+
+// PageBuilder creates a new page for the exported route
+type PageBuilder func() PageElement
+
+// Exporter writes pages for the routes as a static site: each route
+// is written as an HTML file, links to the exported routes are rewritten
+// to relative file paths, registered styles and scripts are written
+// once as content-hashed assets, along with sitemap.xml and 404.html
+type Exporter struct {
+	baseUrl  string
+	routes   map[string]PageBuilder
+	notFound PageBuilder
+}
+
+// NewExporter creates Exporter for the site published at baseUrl,
+// that is used for sitemap.xml absolute URLs. Sitemap is not
+// written with an empty baseUrl
+func NewExporter(baseUrl string) *Exporter {
+	return &Exporter{
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
+		routes:  make(map[string]PageBuilder),
+	}
+}
+
+// Route adds route path (e.g. "/", "/updates", "/docs/") and the page
+// builder for it. Routes are written as index.html, updates.html and docs/index.html
+func (e *Exporter) Route(route string, builder PageBuilder) *Exporter {
+	e.routes[route] = builder
+	return e
+}
+
+// NotFound sets page builder for 404.html, ErrorPage is used by default
+func (e *Exporter) NotFound(builder PageBuilder) *Exporter {
+	e.notFound = builder
+	return e
+}
+
+// Export writes the site to the directory, creating it as needed
+func (e *Exporter) Export(dir string) error {
+	assets := NewAssetStore("/" + exportAssetsDir + "/")
+
+	routes := slices.Sorted(maps.Keys(e.routes))
+	for _, route := range routes {
+		if err := e.exportPage(dir, routeFile(route), e.routes[route], assets); err != nil {
+			return fmt.Errorf("export %s: %w", route, err)
+		}
+	}
+
+	notFound := e.notFound
+	if notFound == nil {
+		notFound = func() PageElement {
+			return ErrorPage(ErrNotFound(nil))
+		}
+	}
+	if err := e.exportPage(dir, exportNotFound, notFound, assets); err != nil {
+		return fmt.Errorf("export %s: %w", exportNotFound, err)
+	}
+
+	for _, name := range assets.Names() {
+		if content, ok := assets.Content(name); ok {
+			if err := writeExportFile(dir, path.Join(exportAssetsDir, name), content); err != nil {
+				return err
+			}
+		}
+	}
+
+	if e.baseUrl == "" {
+		return nil
+	}
+
+	return e.exportSitemap(dir, routes)
+}
+
+func (e *Exporter) exportPage(dir, file string, builder PageBuilder, assets *AssetStore) error {
+	p := builder()
+	p.UseAssetStore(assets)
+	// deferred elements content and styles registered
+	// by their producers need links rewritten as well
+	if pe, ok := p.(*pageElement); ok {
+		pe.resolveDeferred()
+	}
+	e.rewriteLinks(p, file)
+
+	buf := new(bytes.Buffer)
+	if err := p.Write(buf); err != nil {
+		return err
+	}
+
+	return writeExportFile(dir, file, buf.Bytes())
+}
+
+// rewriteLinks replaces href and src attributes values that point
+// to the exported routes or assets with paths relative to the page file.
+// 404.html is served for any missing path, so it gets root paths instead
+func (e *Exporter) rewriteLinks(p PageElement, file string) {
+	for _, el := range p.QuerySelectorAll("[href], [src]") {
+		for _, name := range []string{attr.Href, attr.Src} {
+			value := el.GetAttribute(name)
+			if !strings.HasPrefix(value, "/") || strings.HasPrefix(value, "//") {
+				continue
+			}
+
+			route, suffix := value, ""
+			if index := strings.IndexAny(value, "?#"); index >= 0 {
+				route, suffix = value[:index], value[index:]
+			}
+
+			var target string
+			if strings.HasPrefix(route, "/"+exportAssetsDir+"/") {
+				target = strings.TrimPrefix(route, "/")
+			} else if _, ok := e.routes[route]; ok {
+				target = routeFile(route)
+			} else {
+				continue
+			}
+
+			if file == exportNotFound {
+				target = "/" + target
+			} else {
+				target = relativePath(file, target)
+			}
+			el.SetAttribute(name, target+suffix)
+		}
+	}
+}
+
+type sitemapUrl struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemapUrlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Urls    []sitemapUrl `xml:"url"`
+}
+
+func (e *Exporter) exportSitemap(dir string, routes []string) error {
+	urlSet := sitemapUrlSet{Xmlns: sitemapXmlns}
+	for _, route := range routes {
+		loc := e.baseUrl + "/"
+		if file := routeFile(route); path.Base(file) == "index.html" {
+			loc += strings.TrimSuffix(file, "index.html")
+		} else {
+			loc += file
+		}
+		urlSet.Urls = append(urlSet.Urls, sitemapUrl{Loc: loc})
+	}
+
+	sitemap, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeExportFile(dir, exportSitemap, append([]byte(xml.Header), sitemap...))
+}
+
+func writeExportFile(dir, file string, content []byte) error {
+	absPath := filepath.Join(dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(absPath), exportDirFileMode); err != nil {
+		return err
+	}
+	return os.WriteFile(absPath, content, 0644)
+}
+
+// routeFile returns file path for the route: "/" is index.html,
+// "/docs/" is docs/index.html, "/updates" is updates.html.
+// Routes with an extension (e.g. "/feed.xml") are used as is
+func routeFile(route string) string {
+	file := strings.TrimPrefix(route, "/")
+	switch {
+	case file == "" || strings.HasSuffix(file, "/"):
+		return file + "index.html"
+	case path.Ext(file) != "":
+		return file
+	default:
+		return file + ".html"
+	}
+}
+
+func relativePath(from, to string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(to))
+	if err != nil {
+		return "/" + to
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,213 @@
+package compton
+
+import (
+	"encoding/xml"
+	"golang.org/x/net/html/atom"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRouteFile(t *testing.T) {
+	tests := []struct {
+		route string
+		want  string
+	}{
+		{"/", "index.html"},
+		{"", "index.html"},
+		{"/updates", "updates.html"},
+		{"/docs/", "docs/index.html"},
+		{"/docs/intro", "docs/intro.html"},
+		{"/docs/api/", "docs/api/index.html"},
+		{"/feed.xml", "feed.xml"},
+		{"/docs/page.html", "docs/page.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			if got := routeFile(tt.route); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want string
+	}{
+		{"index.html", "updates.html", "updates.html"},
+		{"index.html", "docs/index.html", "docs/index.html"},
+		{"docs/index.html", "index.html", "../index.html"},
+		{"docs/index.html", "docs/intro.html", "intro.html"},
+		{"docs/api/index.html", "assets/a.css", "../../assets/a.css"},
+		{"updates.html", "updates.html", "updates.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := relativePath(tt.from, tt.to); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func exportLink(href string) Element {
+	link := AtomicElement(atom.A)
+	link.SetAttribute("href", href)
+	return link
+}
+
+func exportPageBuilder(title string, hrefs ...string) PageBuilder {
+	return func() PageElement {
+		p := Page(title)
+		for _, href := range hrefs {
+			p.Append(exportLink(href))
+		}
+		return p
+	}
+}
+
+var hrefRegexp = regexp.MustCompile(`<a href='([^']*)'>`)
+
+func exportedHrefs(t *testing.T, dir, file string) []string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hrefs []string
+	for _, match := range hrefRegexp.FindAllStringSubmatch(string(content), -1) {
+		hrefs = append(hrefs, match[1])
+	}
+	return hrefs
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+
+	deferredPage := func() PageElement {
+		p := Page("deferred")
+		p.Append(Deferred(p, func() Element {
+			return exportLink("/updates")
+		}))
+		return p
+	}
+
+	err := NewExporter("https://example.com/").
+		Route("/", exportPageBuilder("index", "/docs/", "/updates?page=2#top", "/missing", "//cdn.example.com/a.js", "https://example.com/updates", "updates")).
+		Route("/docs/", exportPageBuilder("docs", "/", "/docs/intro", "/updates")).
+		Route("/docs/intro", exportPageBuilder("intro", "/docs/", "/")).
+		Route("/updates", deferredPage).
+		NotFound(exportPageBuilder("not found", "/", "/docs/")).
+		Export(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linksTests := []struct {
+		file string
+		want []string
+	}{
+		{"index.html", []string{"docs/index.html", "updates.html?page=2#top", "/missing", "//cdn.example.com/a.js", "https://example.com/updates", "updates"}},
+		{"docs/index.html", []string{"../index.html", "intro.html", "../updates.html"}},
+		{"docs/intro.html", []string{"index.html", "../index.html"}},
+		{"updates.html", []string{"updates.html"}},
+		{"404.html", []string{"/index.html", "/docs/index.html"}},
+	}
+
+	for _, tt := range linksTests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := exportedHrefs(t, dir, tt.file); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("assets", func(t *testing.T) {
+		assets, err := os.ReadDir(filepath.Join(dir, exportAssetsDir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(assets) == 0 {
+			t.Fatal("no assets")
+		}
+
+		index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		notFound, err := os.ReadFile(filepath.Join(dir, exportNotFound))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, asset := range assets {
+			name := path.Join(exportAssetsDir, asset.Name())
+			if !strings.Contains(string(index), "'"+name+"'") {
+				t.Errorf("index.html doesn't refer to %s", name)
+			}
+			if !strings.Contains(string(notFound), "'/"+name+"'") {
+				t.Errorf("404.html doesn't refer to /%s", name)
+			}
+		}
+	})
+
+	t.Run("sitemap", func(t *testing.T) {
+		content, err := os.ReadFile(filepath.Join(dir, exportSitemap))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var urlSet sitemapUrlSet
+		if err := xml.Unmarshal(content, &urlSet); err != nil {
+			t.Fatal(err)
+		}
+		var locs []string
+		for _, u := range urlSet.Urls {
+			locs = append(locs, u.Loc)
+		}
+		want := []string{
+			"https://example.com/",
+			"https://example.com/docs/",
+			"https://example.com/docs/intro.html",
+			"https://example.com/updates.html",
+		}
+		if !slices.Equal(locs, want) {
+			t.Errorf("got %v, want %v", locs, want)
+		}
+		if urlSet.Xmlns != sitemapXmlns {
+			t.Errorf("got xmlns %q, want %q", urlSet.Xmlns, sitemapXmlns)
+		}
+	})
+}
+
+func TestExportWithoutBaseUrl(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := NewExporter("").Route("/", exportPageBuilder("index")).Export(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{"index.html", exportNotFound} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("%s: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, exportSitemap)); !os.IsNotExist(err) {
+		t.Errorf("got %s with empty base URL", exportSitemap)
+	}
+
+	notFound, err := os.ReadFile(filepath.Join(dir, exportNotFound))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(notFound), "404 Not Found") {
+		t.Error("default 404.html is not the not found error page")
+	}
+}
@@ -15,6 +15,12 @@ type FlexItemsElement struct {
 	*BaseElement
 }
 
+func (fie *FlexItemsElement) Clone() Element {
+	return &FlexItemsElement{
+		BaseElement: fie.BaseElement.cloneBase(),
+	}
+}
+
 func (fie *FlexItemsElement) RowGap(sz size.Size) *FlexItemsElement {
 	fie.AddClass(class.RowGap(sz))
 	return fie
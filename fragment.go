@@ -0,0 +1,169 @@
+package compton
+
+import (
+	"embed"
+	_ "embed"
+	"errors"
+	"github.com/boggydigital/compton/consts/class"
+	"github.com/boggydigital/compton/consts/compton_atoms"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+const fragmentTargetAttr = "data-target"
+
+var (
+	//go:embed "script/fragment.js"
+	scriptFragment []byte
+)
+
+// FragmentElement renders element subtree to update the target element
+// of the page in place (see RegisterFragmentSwap for the client script).
+// FragmentElement is a Registrar: components created with it as a Registrar
+// get their styles, requirements and deferrals written with the fragment,
+// unless they're known to be registered on the page already.
+// Scripts that wait for DOMContentLoaded won't run for the fragment content
+type FragmentElement struct {
+	*BaseElement
+	registry     map[string]any
+	styles       Element
+	styleClasses Element
+	requirements Element
+	content      Element
+	deferrals    Element
+	errs         []error
+}
+
+func (fe *FragmentElement) Clone() Element {
+	clone := &FragmentElement{
+		BaseElement: fe.BaseElement.cloneBase(),
+		registry:    maps.Clone(fe.registry),
+		errs:        slices.Clone(fe.errs),
+	}
+	if parts := clone.Children; len(parts) == 5 {
+		clone.styles, clone.styleClasses, clone.requirements, clone.content, clone.deferrals =
+			parts[0], parts[1], parts[2], parts[3], parts[4]
+	}
+	rebindRegistrars(fe, clone)
+	return clone
+}
+
+func (fe *FragmentElement) Append(children ...Element) {
+	fe.content.Append(children...)
+}
+
+func (fe *FragmentElement) Prepend(children ...Element) {
+	fe.content.Prepend(children...)
+}
+
+func (fe *FragmentElement) InsertBefore(newChild, refChild Element) bool {
+	return fe.content.InsertBefore(newChild, refChild)
+}
+
+func (fe *FragmentElement) RemoveChild(child Element) bool {
+	return fe.content.RemoveChild(child)
+}
+
+func (fe *FragmentElement) ReplaceChild(newChild, oldChild Element) bool {
+	return fe.content.ReplaceChild(newChild, oldChild)
+}
+
+func (fe *FragmentElement) Write(w io.Writer) error {
+	if err := fe.Validate(); err != nil {
+		return err
+	}
+	classNames := collectClassNames(fe.content)
+	classNames = append(classNames, collectClassNames(fe.requirements)...)
+	classNames = append(classNames, collectClassNames(fe.deferrals)...)
+	for _, child := range slices.Clone(fe.styleClasses.GetChildren()) {
+		fe.styleClasses.RemoveChild(child)
+	}
+	if sc := class.StyleClassesFor(classNames...); len(sc) > 0 {
+		fe.styleClasses.Append(Style(sc))
+	}
+	return fe.BaseElement.Write(w)
+}
+
+func (fe *FragmentElement) WriteResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	return fe.Write(w)
+}
+
+func (fe *FragmentElement) RegisterStyles(efs embed.FS, names ...string) {
+	for _, name := range names {
+		if _, ok := fe.registry[name]; !ok {
+			fe.registry[name] = nil
+			if content, err := readStyle(efs, name); err == nil {
+				if len(content) > 0 {
+					fe.styles.Append(Style(content))
+				}
+			} else {
+				fe.errs = append(fe.errs, ErrRegistration(name, err))
+			}
+		}
+	}
+}
+
+func (fe *FragmentElement) IsRegistered(name string) bool {
+	_, ok := fe.registry[name]
+	return ok
+}
+
+func (fe *FragmentElement) registrationError(err error) {
+	fe.errs = append(fe.errs, err)
+}
+
+// Validate returns registration errors, fragments with registration errors are not written
+func (fe *FragmentElement) Validate() error {
+	return errors.Join(fe.errs...)
+}
+
+func (fe *FragmentElement) RegisterRequirements(name string, elements ...Element) {
+	if _, ok := fe.registry[name]; !ok {
+		fe.registry[name] = nil
+		fe.requirements.Append(elements...)
+	}
+}
+
+func (fe *FragmentElement) RegisterDeferrals(name string, elements ...Element) {
+	if _, ok := fe.registry[name]; !ok {
+		fe.registry[name] = nil
+		fe.deferrals.Append(elements...)
+	}
+}
+
+// Fragment creates fragment for the element with targetId. Names that
+// are already registered on the page (see PageElement.RegisteredNames)
+// are not written with the fragment
+func Fragment(targetId string, registered ...string) *FragmentElement {
+	fe := &FragmentElement{
+		BaseElement:  NewElement(tacMarkup(compton_atoms.Fragment)),
+		registry:     make(map[string]any),
+		styles:       NewElement(contentMarkup(compton_atoms.Placeholder)),
+		styleClasses: NewElement(contentMarkup(compton_atoms.Placeholder)),
+		requirements: NewElement(tacMarkup(compton_atoms.Requirements)),
+		content:      NewElement(tacMarkup(compton_atoms.Content)),
+		deferrals:    NewElement(tacMarkup(compton_atoms.Deferrals)),
+	}
+
+	for _, name := range registered {
+		fe.registry[name] = nil
+	}
+
+	fe.SetAttribute(fragmentTargetAttr, targetId)
+	fe.BaseElement.Append(fe.styles, fe.styleClasses, fe.requirements, fe.content, fe.deferrals)
+
+	return fe
+}
+
+// RegisterFragmentSwap adds fetchFragment(url) client function,
+// that requests a fragment and swaps it into the target element.
+// Fragment styles are adopted as constructed stylesheets. Fragment
+// scripts need the page policy nonce (see ContentSecurityPolicy.Nonce),
+// since their hashes are not in the page policy: without a nonce
+// fetchFragment throws for the fragments with scripts
+func RegisterFragmentSwap(r Registrar) {
+	r.RegisterDeferrals(compton_atoms.ScriptName(compton_atoms.Fragment), ScriptAsync(scriptFragment))
+}
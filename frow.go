@@ -15,6 +15,17 @@ type FrowElement struct {
 	r Registrar
 }
 
+func (f *FrowElement) Clone() Element {
+	return &FrowElement{
+		BaseElement: f.BaseElement.cloneBase(),
+		r:           f.r,
+	}
+}
+
+func (f *FrowElement) bindRegistrar(registrars map[Registrar]Registrar) {
+	f.r = boundRegistrar(f.r, registrars)
+}
+
 func (f *FrowElement) Elements(elements ...Element) *FrowElement {
 	if fi := f.GetFirstElementByTagName(compton_atoms.FlexItems); fi != nil {
 		fi.Append(elements...)
@@ -14,6 +14,12 @@ type FspanElement struct {
 	*BaseElement
 }
 
+func (fse *FspanElement) Clone() Element {
+	return &FspanElement{
+		BaseElement: fse.BaseElement.cloneBase(),
+	}
+}
+
 func (fse *FspanElement) ForegroundColor(c color.Color) *FspanElement {
 	fse.AddClass(class.ForegroundColor(c))
 	return fse
@@ -12,6 +12,12 @@ type GridItemsElement struct {
 	*BaseElement
 }
 
+func (gie *GridItemsElement) Clone() Element {
+	return &GridItemsElement{
+		BaseElement: gie.BaseElement.cloneBase(),
+	}
+}
+
 func (gie *GridItemsElement) RowGap(sz size.Size) *GridItemsElement {
 	gie.AddClass(class.RowGap(sz))
 	return gie
@@ -1,7 +1,6 @@
 package compton
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"embed"
 	"encoding/base64"
@@ -10,6 +9,9 @@ import (
 	"github.com/boggydigital/compton/consts/loading"
 	"golang.org/x/net/html/atom"
 	"io"
+	"maps"
+	"slices"
+	"strings"
 )
 
 var (
@@ -26,11 +28,18 @@ type TextElement struct {
 	content string
 }
 
+func (t *TextElement) Clone() Element {
+	return &TextElement{
+		BaseElement: t.BaseElement.cloneBase(),
+		content:     t.content,
+	}
+}
+
 func (t *TextElement) Append(_ ...Element) {
 }
 
 func (t *TextElement) Write(w io.Writer) error {
-	if _, err := io.WriteString(w, t.content); err != nil {
+	if _, err := io.WriteString(w, escapeText(t.content)); err != nil {
 		return err
 	}
 	return nil
@@ -43,6 +52,43 @@ func Text(content string) Element {
 	}
 }
 
+/* trusted markup that is written as-is, without escaping */
+
+type RawHTMLElement struct {
+	*BaseElement
+	content string
+}
+
+func (rh *RawHTMLElement) Clone() Element {
+	return &RawHTMLElement{
+		BaseElement: rh.BaseElement.cloneBase(),
+		content:     rh.content,
+	}
+}
+
+func (rh *RawHTMLElement) Append(_ ...Element) {
+}
+
+func (rh *RawHTMLElement) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, rh.content); err != nil {
+		return err
+	}
+	return nil
+}
+
+func RawHTML(content string) Element {
+	return &RawHTMLElement{
+		BaseElement: NewElement(contentMarkup(atom.Plaintext)),
+		content:     content,
+	}
+}
+
+// TrustedHTML is RawHTML, named for the call sites that
+// write markup known to be safe, without escaping
+func TrustedHTML(content string) Element {
+	return RawHTML(content)
+}
+
 /* https://developer.mozilla.org/en-US/docs/Web/HTML/Element/a */
 
 func A(href string) Element {
@@ -85,6 +131,12 @@ type DetailsElement struct {
 	*BaseElement
 }
 
+func (d *DetailsElement) Clone() Element {
+	return &DetailsElement{
+		BaseElement: d.BaseElement.cloneBase(),
+	}
+}
+
 func (d *DetailsElement) AppendSummary(children ...Element) *DetailsElement {
 	var summary Element
 	if summaries := d.GetElementsByTagName(atom.Summary); len(summaries) > 0 {
@@ -277,8 +329,8 @@ func ListItemText(txt string) Element {
 
 func Link(kv map[string]string) Element {
 	link := NewElement(voidTacMarkup(atom.Link))
-	for k, v := range kv {
-		link.SetAttribute(k, v)
+	for _, k := range slices.Sorted(maps.Keys(kv)) {
+		link.SetAttribute(k, kv[k])
 	}
 	return link
 }
@@ -287,8 +339,8 @@ func Link(kv map[string]string) Element {
 
 func Meta(kv map[string]string) Element {
 	meta := NewElement(voidTacMarkup(atom.Meta))
-	for k, v := range kv {
-		meta.SetAttribute(k, v)
+	for _, k := range slices.Sorted(maps.Keys(kv)) {
+		meta.SetAttribute(k, kv[k])
 	}
 	return meta
 }
@@ -347,9 +399,18 @@ var b64 = base64.StdEncoding
 
 type ScriptElement struct {
 	*BaseElement
+	code []byte
 	hash []byte
 }
 
+func (se *ScriptElement) Clone() Element {
+	return &ScriptElement{
+		BaseElement: se.BaseElement.cloneBase(),
+		code:        slices.Clone(se.code),
+		hash:        slices.Clone(se.hash),
+	}
+}
+
 func computeSha256(reader io.Reader) ([]byte, error) {
 	h := sha256.New()
 	var err error
@@ -369,13 +430,24 @@ func (se *ScriptElement) Sha256() string {
 func Script(code []byte) *ScriptElement {
 	script := &ScriptElement{
 		BaseElement: NewElement(tacMarkup(atom.Script)),
+		code:        code,
 	}
 
-	if hash, err := computeSha256(bytes.NewReader(code)); err == nil {
+	// hash is computed on escaped code, since that's what the browser will get
+	escapedCode := escapeRawText(atom.Script.String(), string(code))
+
+	if hash, err := computeSha256(strings.NewReader(escapedCode)); err == nil {
 		script.hash = hash
 	}
 
-	script.Append(Text(string(code)))
+	script.Append(RawHTML(escapedCode))
+	return script
+}
+
+// ScriptSrc creates external script element
+func ScriptSrc(src string) Element {
+	script := NewElement(tacMarkup(atom.Script))
+	script.SetAttribute(attr.Src, src)
 	return script
 }
 
@@ -405,9 +477,38 @@ func SpanText(txt string) Element {
 
 /* https://developer.mozilla.org/en-US/docs/Web/HTML/Element/style */
 
-func Style(styles []byte) Element {
-	style := NewElement(tacMarkup(atom.Style))
-	style.Append(Text(string(styles)))
+type StyleElement struct {
+	*BaseElement
+	hash []byte
+}
+
+func (se *StyleElement) Clone() Element {
+	return &StyleElement{
+		BaseElement: se.BaseElement.cloneBase(),
+		hash:        slices.Clone(se.hash),
+	}
+}
+
+func (se *StyleElement) Sha256() string {
+	if len(se.hash) > 0 {
+		return "sha256-" + b64.EncodeToString(se.hash)
+	}
+	return ""
+}
+
+func Style(styles []byte) *StyleElement {
+	style := &StyleElement{
+		BaseElement: NewElement(tacMarkup(atom.Style)),
+	}
+
+	// hash is computed on escaped styles, since that's what the browser will get
+	escapedStyles := escapeRawText(atom.Style.String(), string(styles))
+
+	if hash, err := computeSha256(strings.NewReader(escapedStyles)); err == nil {
+		style.hash = hash
+	}
+
+	style.Append(RawHTML(escapedStyles))
 	return style
 }
 
@@ -17,10 +17,44 @@ type IframeExpandElement struct {
 	iframe Element
 }
 
+func (ife *IframeExpandElement) Clone() Element {
+	clone := &IframeExpandElement{r: ife.r}
+	ife.BaseElement.copyTo(&clone.BaseElement)
+	clone.iframe = clone.Children[0]
+	return clone
+}
+
+func (ife *IframeExpandElement) bindRegistrar(registrars map[Registrar]Registrar) {
+	ife.r = boundRegistrar(ife.r, registrars)
+}
+
 func (ife *IframeExpandElement) Write(w io.Writer) error {
+	if ok, err := writeOverride(&ife.BaseElement, w); ok {
+		return err
+	}
 	return ife.iframe.Write(w)
 }
 
+func (ife *IframeExpandElement) Append(children ...Element) {
+	ife.iframe.Append(children...)
+}
+
+func (ife *IframeExpandElement) Prepend(children ...Element) {
+	ife.iframe.Prepend(children...)
+}
+
+func (ife *IframeExpandElement) InsertBefore(newChild, refChild Element) bool {
+	return ife.iframe.InsertBefore(newChild, refChild)
+}
+
+func (ife *IframeExpandElement) RemoveChild(child Element) bool {
+	return ife.iframe.RemoveChild(child)
+}
+
+func (ife *IframeExpandElement) ReplaceChild(newChild, oldChild Element) bool {
+	return ife.iframe.ReplaceChild(newChild, oldChild)
+}
+
 // IframeExpandHost creates iframe-expand that will expand height to content height.
 // In order to achieve that, two scripts need to be present
 // script/receive.js on the host page (the page that contains iframe element)
@@ -36,8 +70,14 @@ func IframeExpandHost(r Registrar, id, src string) Element {
 	r.RegisterRequirements(compton_atoms.ScriptName(compton_atoms.IframeExpandHost),
 		Script(scriptIframeExpandReceive))
 
-	return &IframeExpandElement{
+	ife := &IframeExpandElement{
+		BaseElement: BaseElement{
+			TagName: compton_atoms.IframeExpandHost,
+		},
 		r:      r,
 		iframe: iframe,
 	}
+	ife.BaseElement.Append(iframe)
+
+	return ife
 }
@@ -31,6 +31,22 @@ type InputElement struct {
 	dataList Element
 }
 
+// Clone shares the datalist: it's registered with the Registrar as a deferral,
+// so the clone list attribute refers to the same datalist (page clones
+// copy deferrals, including the datalist, with the same id)
+func (ie *InputElement) Clone() Element {
+	return &InputElement{
+		BaseElement: ie.BaseElement.cloneBase(),
+		r:           ie.r,
+		it:          ie.it,
+		dataList:    ie.dataList,
+	}
+}
+
+func (ie *InputElement) bindRegistrar(registrars map[Registrar]Registrar) {
+	ie.r = boundRegistrar(ie.r, registrars)
+}
+
 func (ie *InputElement) SetPlaceholder(placeholder string) *InputElement {
 	ie.SetAttribute("placeholder", placeholder)
 	return ie
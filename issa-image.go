@@ -2,6 +2,7 @@ package compton
 
 import (
 	_ "embed"
+	"github.com/boggydigital/compton/consts/attr"
 	"github.com/boggydigital/compton/consts/class"
 	"github.com/boggydigital/compton/consts/compton_atoms"
 	"github.com/boggydigital/compton/consts/size"
@@ -18,6 +19,13 @@ type IssaImageElement struct {
 	dehydrated bool
 }
 
+func (iie *IssaImageElement) Clone() Element {
+	return &IssaImageElement{
+		BaseElement: iie.BaseElement.cloneBase(),
+		dehydrated:  iie.dehydrated,
+	}
+}
+
 func (iie *IssaImageElement) Width(s size.Size) *IssaImageElement {
 	iie.AddClass(class.Width(s))
 	return iie
@@ -67,7 +75,7 @@ func issaImage(r Registrar, bgHex, placeholder, poster string, dehydrated bool)
 	placeholderImg.AddClass(classes...)
 
 	posterImg := ImageLazy("")
-	posterImg.SetAttribute("data-src", poster)
+	posterImg.SetAttribute(attr.DataSrc, poster)
 	posterImg.AddClass("poster", "loading")
 	ii.Append(placeholderImg, posterImg)
 
@@ -21,6 +21,13 @@ type LabelsElement struct {
 	container Element
 }
 
+func (lse *LabelsElement) Clone() Element {
+	clone := &LabelsElement{}
+	lse.BaseElement.copyTo(&clone.BaseElement)
+	clone.container = clone.Children[0]
+	return clone
+}
+
 func createLabelElement(fmtLabel FormattedLabel) Element {
 	label := ListItemText(fmtLabel.Title)
 	cs := []string{"label", fmtLabel.Property, fmtLabel.Title, fmtLabel.Class}
@@ -29,29 +36,54 @@ func createLabelElement(fmtLabel FormattedLabel) Element {
 }
 
 func (lse *LabelsElement) unorderedList() Element {
-	if uls := lse.container.GetElementsByTagName(atom.Ul); len(uls) > 0 {
-		return uls[0]
-	} else {
-		panic("labels missing ul element")
-	}
+	return lse.container.GetFirstElementByTagName(atom.Ul)
 }
 
 func (lse *LabelsElement) Write(w io.Writer) error {
+	if ok, err := writeOverride(&lse.BaseElement, w); ok {
+		return err
+	}
 	return lse.container.Write(w)
 }
 
+func (lse *LabelsElement) Append(children ...Element) {
+	lse.container.Append(children...)
+}
+
+func (lse *LabelsElement) Prepend(children ...Element) {
+	lse.container.Prepend(children...)
+}
+
+func (lse *LabelsElement) InsertBefore(newChild, refChild Element) bool {
+	return lse.container.InsertBefore(newChild, refChild)
+}
+
+func (lse *LabelsElement) RemoveChild(child Element) bool {
+	return lse.container.RemoveChild(child)
+}
+
+func (lse *LabelsElement) ReplaceChild(newChild, oldChild Element) bool {
+	return lse.container.ReplaceChild(newChild, oldChild)
+}
+
 func (lse *LabelsElement) FontSize(s size.Size) *LabelsElement {
-	lse.unorderedList().AddClass(class.FontSize(s))
+	if ul := lse.unorderedList(); ul != nil {
+		ul.AddClass(class.FontSize(s))
+	}
 	return lse
 }
 
 func (lse *LabelsElement) RowGap(s size.Size) *LabelsElement {
-	lse.unorderedList().AddClass(class.RowGap(s))
+	if ul := lse.unorderedList(); ul != nil {
+		ul.AddClass(class.RowGap(s))
+	}
 	return lse
 }
 
 func (lse *LabelsElement) ColumnGap(s size.Size) *LabelsElement {
-	lse.unorderedList().AddClass(class.ColumnGap(s))
+	if ul := lse.unorderedList(); ul != nil {
+		ul.AddClass(class.ColumnGap(s))
+	}
 	return lse
 }
 
@@ -73,6 +105,7 @@ func Labels(r Registrar, fmtLabels ...FormattedLabel) *LabelsElement {
 		}
 	}
 	lse.container.Append(ul)
+	lse.BaseElement.Append(lse.container)
 
 	r.RegisterStyles(DefaultStyle,
 		compton_atoms.StyleName(compton_atoms.Labels))
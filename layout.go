@@ -0,0 +1,86 @@
+package compton
+
+import (
+	"github.com/boggydigital/compton/consts/align"
+	"github.com/boggydigital/compton/consts/direction"
+	"net/http"
+	"strconv"
+)
+
+// LayoutShell builds shared part of the page (e.g. registers styles, appends
+// navigation and footer) and returns the region element, that the page content
+// (or nested layout shell) is appended to. Nil region keeps the current one
+type LayoutShell func(p PageElement) Element
+
+// Layout defines shared shell of the pages once, to create
+// a page per request with Page and append content to its region
+type Layout struct {
+	parent *Layout
+	shell  LayoutShell
+}
+
+func NewLayout(shell LayoutShell) *Layout {
+	return &Layout{shell: shell}
+}
+
+// Nest creates layout, which shell is built inside this layout region,
+// e.g. a section layout inside the site layout
+func (l *Layout) Nest(shell LayoutShell) *Layout {
+	return &Layout{parent: l, shell: shell}
+}
+
+func (l *Layout) build(p *pageElement) {
+	if l.parent != nil {
+		l.parent.build(p)
+	}
+	if l.shell == nil {
+		return
+	}
+	if region := l.shell(p); region != nil {
+		p.setRegion(region)
+	}
+}
+
+// Page creates page with layout shells (outer to inner), page
+// Append and other children methods use the innermost region
+func (l *Layout) Page(title string) PageElement {
+	p := Page(title).(*pageElement)
+	l.build(p)
+	return p
+}
+
+// ErrorPage creates layout page for the errors, with the first error
+// status code and text heading, followed by the errors messages
+func (l *Layout) ErrorPage(errs ...error) PageElement {
+	var firstErr error
+	for _, err := range errs {
+		if err != nil {
+			firstErr = err
+			break
+		}
+	}
+	statusCode := StatusCode(firstErr)
+	if firstErr == nil {
+		statusCode = http.StatusInternalServerError
+	}
+	title := strconv.Itoa(statusCode) + " " + http.StatusText(statusCode)
+
+	p := l.Page(title).(*pageElement)
+
+	p.errorsList = FlexItems(p, direction.Column).AlignItems(align.Center)
+	content := FlexItems(p, direction.Column).AlignItems(align.Center)
+	content.Append(HeadingText(title, 1), p.errorsList)
+	p.Append(content)
+
+	if firstErr == nil {
+		p.pageErrors = append(p.pageErrors, ErrStatus(statusCode, nil))
+	}
+	p.Error(errs...)
+
+	return p
+}
+
+// ErrorPage creates page for the errors without layout (see Layout.ErrorPage)
+func ErrorPage(errs ...error) PageElement {
+	return NewLayout(nil).ErrorPage(errs...)
+}
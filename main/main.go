@@ -23,6 +23,11 @@ import (
 var appStyles embed.FS
 
 func main() {
+	if len(os.Args) > 2 && os.Args[1] == "export" {
+		exportSite(os.Args[2])
+		return
+	}
+
 	writeTestPage()
 	//writeColors()
 	//writeIframeContent()
@@ -30,49 +35,94 @@ func main() {
 	//writeSvgUsePage()
 }
 
-func writeTestPage() {
-	p := compton.Page("test")
-	p.RegisterStyles(appStyles, "styles.css")
+func siteLayout() *compton.Layout {
+	return compton.NewLayout(func(p compton.PageElement) compton.Element {
+		p.RegisterStyles(appStyles, "styles.css")
 
-	s := compton.FlexItems(p, direction.Column)
+		s := compton.FlexItems(p, direction.Column)
 
-	topNavLinks := map[string]string{
-		"Updates": "/updates",
-		"Search":  "/search",
-	}
+		topNavLinks := map[string]string{
+			"Updates": "/updates",
+			"Search":  "/search",
+		}
 
-	topNavIcons := map[string]compton.Symbol{
-		"Updates": compton.Sparkle,
-		"Search":  compton.Search,
-	}
+		topNavIcons := map[string]compton.Symbol{
+			"Updates": compton.Sparkle,
+			"Search":  compton.Search,
+		}
+
+		targets := compton.TextLinks(
+			topNavLinks,
+			"Search",
+			"Updates", "Search")
+		compton.SetIcons(targets, topNavIcons)
+
+		topNav := compton.NavLinksTargets(p, targets...)
+
+		navLinks := map[string]string{
+			"New":      "/new",
+			"Owned":    "/owned",
+			"Wishlist": "/wishlist",
+			"Sale":     "/sale",
+			"All":      "/all",
+		}
 
-	targets := compton.TextLinks(
-		topNavLinks,
-		"Search",
-		"Updates", "Search")
-	compton.SetIcons(targets, topNavIcons)
+		nav := compton.NavLinksTargets(p,
+			compton.TextLinks(
+				navLinks,
+				"New",
+				"New",
+				"Owned",
+				"Wishlist",
+				"Sale",
+				"All")...)
+
+		s.Append(compton.FICenter(p, topNav, nav))
+
+		content := compton.Content()
+		s.Append(content)
+
+		footer := compton.FICenter(p)
+
+		div := compton.Fspan(p, "").ForegroundColor(color.Gray).FontSize(size.Small)
+
+		div.Append(compton.Text("Last updated: "),
+			compton.TimeText(time.Now().Format("2006-01-02 15:04:05")))
+
+		footer.Append(div)
+
+		s.Append(footer)
+
+		p.Append(s)
+
+		return content
+	})
+}
+
+func exportSite(dir string) {
+	baseUrl := ""
+	if len(os.Args) > 3 {
+		baseUrl = os.Args[3]
+	}
 
-	topNav := compton.NavLinksTargets(p, targets...)
+	exporter := compton.NewExporter(baseUrl).
+		Route("/", testPage).
+		Route("/colors", colorsPage).
+		Route("/issa", issaPage).
+		Route("/svg-use", svgUsePage).
+		NotFound(func() compton.PageElement {
+			return siteLayout().ErrorPage(compton.ErrNotFound(nil))
+		})
 
-	navLinks := map[string]string{
-		"New":      "/new",
-		"Owned":    "/owned",
-		"Wishlist": "/wishlist",
-		"Sale":     "/sale",
-		"All":      "/all",
+	if err := exporter.Export(dir); err != nil {
+		panic(err)
 	}
 
-	nav := compton.NavLinksTargets(p,
-		compton.TextLinks(
-			navLinks,
-			"New",
-			"New",
-			"Owned",
-			"Wishlist",
-			"Sale",
-			"All")...)
+	fmt.Println("file://" + filepath.Join(dir, "index.html"))
+}
 
-	s.Append(compton.FICenter(p, topNav, nav))
+func testPage() compton.PageElement {
+	p := siteLayout().Page("test")
 
 	filterSearchTitle := compton.Fspan(p, "Filter & Search").
 		FontWeight(font_weight.Bolder).
@@ -118,9 +168,9 @@ func writeTestPage() {
 	form.Append(formStack)
 
 	dsFilterSearch.Append(form)
-	s.Append(dsFilterSearch)
+	p.Append(dsFilterSearch)
 
-	s.Append(qf)
+	p.Append(qf)
 
 	tvsTitle := compton.Fspan(p, "Title Values").
 		FontWeight(font_weight.Bolder).
@@ -150,14 +200,14 @@ func writeTestPage() {
 		FontWeight(font_weight.Bolder)
 	dsValues := compton.DSSmall(p, dsTitle, false)
 	for ii := range 10 {
-		element := compton.Fspan(p, "Element "+strconv.Itoa(ii)+"&nbsp;").ForegroundColor(color.Gray)
+		element := compton.Fspan(p, "Element "+strconv.Itoa(ii)+"\u00a0").ForegroundColor(color.Gray)
 		dsValues.Append(element)
 	}
 	tv7.Append(dsValues)
 
 	tvGrid.Append(tv1, tv2, tv3, tv4, tv5, tv6, tv7)
 	dsTitleValues.Append(tvGrid)
-	s.Append(dsTitleValues)
+	p.Append(dsTitleValues)
 
 	switchesTitle := compton.Fspan(p, "Switches").
 		FontWeight(font_weight.Bolder).
@@ -183,7 +233,7 @@ func writeTestPage() {
 
 	dsSwitches.Append(swColumn)
 
-	s.Append(dsSwitches)
+	p.Append(dsSwitches)
 
 	fr := compton.Frow(p)
 	fr.IconColor(compton.Circle, color.Indigo).
@@ -191,20 +241,13 @@ func writeTestPage() {
 		PropVal("Property", "Value").
 		Highlight("Highlight")
 
-	s.Append(compton.FICenter(p, fr))
-
-	footer := compton.FICenter(p)
-
-	div := compton.Fspan(p, "").ForegroundColor(color.Gray).FontSize(size.Small)
-
-	div.Append(compton.Text("Last updated: "),
-		compton.TimeText(time.Now().Format("2006-01-02 15:04:05")))
-
-	footer.Append(div)
+	p.Append(compton.FICenter(p, fr))
 
-	s.Append(footer)
+	return p
+}
 
-	p.Append(s)
+func writeTestPage() {
+	p := testPage()
 
 	testPath := filepath.Join(os.TempDir(), "test.html")
 	testFile, err := os.Create(testPath)
@@ -220,8 +263,7 @@ func writeTestPage() {
 	fmt.Println("file://" + testPath)
 }
 
-func writeColors() {
-
+func colorsPage() compton.PageElement {
 	page := compton.Page("Colors")
 	page.RegisterStyles(compton.DefaultStyle, "style/colors.css")
 	page.RegisterStyles(appStyles, "styles.css")
@@ -245,6 +287,12 @@ func writeColors() {
 		}
 	}
 
+	return page
+}
+
+func writeColors() {
+	page := colorsPage()
+
 	colorsPath := filepath.Join(os.TempDir(), "colors.html")
 	colorsFile, err := os.Create(colorsPath)
 	if err != nil {
@@ -318,7 +366,7 @@ func writeIframeContent() {
 	fmt.Println("file://" + iframePath)
 }
 
-func writeIssaPage() {
+func issaPage() compton.PageElement {
 	//hydratedSrc := "data:image/gif;base64,R0lGODlhZAAuAAAAACwAAAAAZAAuAIcqKioqKlQqKn4qKqgqKtIqKvwqVCoqVFQqVH4qVKgqVNIqVPwqfioqflQqfn4qfqgqftIqfvwqqCoqqFQqqH4qqKgqqNIqqPwq0ioq0lQq0n4q0qgq0tIq0vwq/Coq/FQq/H4q/Kgq/NIq/PxUKipUKlRUKn5UKqhUKtJUKvxUVCpUVFRUVH5UVKhUVNJUVPxUfipUflRUfn5UfqhUftJUfvxUqCpUqFRUqH5UqKhUqNJUqPxU0ipU0lRU0n5U0qhU0tJU0vxU/CpU/FRU/H5U/KhU/NJU/Px+Kip+KlR+Kn5+Kqh+KtJ+Kvx+VCp+VFR+VH5+VKh+VNJ+VPx+fip+flR+fn5+fqh+ftJ+fvx+qCp+qFR+qH5+qKh+qNJ+qPx+0ip+0lR+0n5+0qh+0tJ+0vx+/Cp+/FR+/H5+/Kh+/NJ+/PyoKiqoKlSoKn6oKqioKtKoKvyoVCqoVFSoVH6oVKioVNKoVPyofiqoflSofn6ofqioftKofvyoqCqoqFSoqH6oqKioqNKoqPyo0iqo0lSo0n6o0qio0tKo0vyo/Cqo/FSo/H6o/Kio/NKo/PzSKirSKlTSKn7SKqjSKtLSKvzSVCrSVFTSVH7SVKjSVNLSVPzSfirSflTSfn7SfqjSftLSfvzSqCrSqFTSqH7SqKjSqNLSqPzS0irS0lTS0n7S0qjS0tLS0vzS/CrS/FTS/H7S/KjS/NLS/Pz8Kir8KlT8Kn78Kqj8KtL8Kvz8VCr8VFT8VH78VKj8VNL8VPz8fir8flT8fn78fqj8ftL8fvz8qCr8qFT8qH78qKj8qNL8qPz80ir80lT80n780qj80tL80vz8/Cr8/FT8/H78/Kj8/NL8/PwAAAAAAP8A/wAA////AAD/AP///wD///9sbGxsbJZsbMBslmxslpZslsBswGxswJZswMCWbGyWbJaWbMCWlmyWlpaWlsCWwGyWwJaWwMDAbGzAbJbAbMDAlmzAlpbAlsDAwGzAwJbAwMAAAAAAAAAAAAAAAAAAAAAI/wCxCRxIsKDBgwgTKlzIsKHDhxAjSpxIsaLFixgzatzIsaNHhgBCfhxJMqRJkyRTYjzJEqVKiwAutpzZsmNMgycfniSBk6bPkwGCBq25MefAn0aPkli6VCDSpyEPSJ16QGiAmxOhsmRK4ikJJF+RIHGKlOlOk1TTUsUq0eTSn1zjct3KRiwSNmzIyuXqxAlXFYBVrFCbFsDVinuZrlgR165jsHvv4p0s8K8TwF1JAO57ue9mwStiyBjtwAECBAeIQtyrgsTVxZxjO5EjR7ZsNpByQ7IECRuAzrL1LrXdd7Ho0chlpGYpkWlgzyFJDGZAhbb167Rly9HNG3fIvrVjc/+1C0AFcSehZax4Ylb1QxK28TyJDs6AgQZVOOnnZAkPHuy08SagJbhBAgAJtV33hBMLfiUHEn59B15sT6wg1lw7NQcebXhw4gRaDRxgQAz6AbPfifpZouJuvOVWFzYIgjcHCU9AyFRdclgS4HWyXdieWxIhYV2HwOAhEgD3TaDCJZwA4+STJj6py5QtuigWjHOkU8JSEDohGY4qhqkij176CNZOPEWEhH8d6mefAQBI1YADK7CRTh53cqJHO3rkgeccl6STziWQ4CUWXiGtEN1wtD3hqKNIPPqEHE+AA06l4Cym6VddHXhgmhAhsZ+TRjLQgAErnIYAC0+oykI6rqb/wwICV7DAwhyF4qVbTJmFJSSlrs6hKgKwqlohAjKcxl5cnwbZ5JOcwAnfCjLMYak8CFT6KrHg3DqrFcgSmtuUkNCy6Fc63kVbtivcmm2rwjqKwBypgqNsWO2B+hAbUDqpAgBCPiGDHOBYIU+1Ah+cpQyXJEfOJVPqwt+UB+KVo4qSOeGAHNR24sCkCOTxsRwbg1NaaexdCNmBEvHbr4dfzUFOJ+Ck00m16ciQh855PNHJE1aMNnOTOloy5Ve6iIkxG3LIYImjnKwwB8k7+7mCjk+cXGNd+LIcERs66pLjk3LQOLMcnHSCdh7k1DO0HJ1wsjM55OTBiaH86oKb2Jlh/yzkDGpbAkyOncxQjwz1cIK2iuCMNsdkQvoYJJeCGwOMMZzQaMUo+nHOST1WkLK5KJwcw8koeLDTRSfAEFiX4GHyBIDRSbMBNOml64cHOHmA0ybm/OFBThV3gfUgU5MDwIYuTuqChwEIns6JKMeIMkoneXA+yijUG6M9IHOMWZfFlqRJQsSWQGgJ6caYHtj7nBgDPCd4VOGpb0m9t5SKU84B/ZrTo94omnGMAh5jFKloRjNGcYxOHFBuYqENl5riFCohAQAdEkX7CogH0EiFCqaz3OXoV5tO5c8hkAHblEpgEjwcQ4GmG2AzRGGJFx6DNs1w4AH94iCmyaEgAEjap//w0D4C6ugYTxDMAVYwCjnwq0nGIJ0T5RCTIwXpUPy7oG/y0AxnmO4JeUicde7moA5xsC6QcOJdqngTHXEJDwo0huvkgBnBFIhp68PcXbpyFLY4BGwFyk35tuhFSzhBD6WoRz1KIYy7HcouL3SixQ7lG1Dp6EBI4GLrFgeJzBxABbRTERs40Qw8mAV/IlFToXIlyK7kwRi06UQJnmCPTgRDGLXDDWQ44YwHEcguvUnl7Kj4lTwAQyzpckIApIIaJ0Ssdt7L0VjI4seGfGkyhvqNEyV2IHAYTUy4sdgo1Wiou6AyJP2pGF4ys0fpbIp2SSMBG7zEBj6esCHyxGZullL/ggBcjRblA8AKtgOJKQGDO63bTaGKZ05UCuRf8pSgb64ppmfqAixICKhwmnMXF70IACVg4fIuWh7dMA8YSVNoIHNlKLIYJJ/HkwyLBGfRZ1KRLVeKCKd0Vc8DMSYkSCjUgeDJPB0VdHyT8SgbquiboRwpOpjES5igNKXwnKkgOQ0VJMSyVagKtFOcAmXEWvc6XbBUppORXVOvkkqCAEwyKnKS5bxkQj8qb5paXVlmuPYWx5jUdXfpjkcds1STsNUlBFGB4g4FNszVlY8FIcFWm7MX3JRvPIcS5C/RlSvx2SUv57wnAPhTl8ACA7JudYpA9hgkHnJKMqYEql/sElG+U6LVOo+cpk8M8phROoGanfLnBXmF2odErjGQScy5mIJRCEVOcvgD4kF8tabfCsQJVLgMYHzzIcNWEySdeglMdCLe8pr3vOhNr3rXy972uve9AgkIADs"
 	//imageSrc := "https://gaugin.frmnt.io/image?id=eaad5d1fce93e36d33b9983fba7edc623e23793e138667aafff6b7a305717c84"
 
@@ -332,6 +380,12 @@ func writeIssaPage() {
 	issaImage := compton.IssaImageDehydrated(p, "", dehydratedSrc, imageSrc)
 	p.Append(issaImage)
 
+	return p
+}
+
+func writeIssaPage() {
+	p := issaPage()
+
 	issaPath := filepath.Join(os.TempDir(), "issa.html")
 	issaFile, err := os.Create(issaPath)
 	if err != nil {
@@ -381,11 +435,17 @@ func createQueryFragment(r compton.Registrar) compton.Element {
 	return compton.FICenter(r, shStack)
 }
 
-func writeSvgUsePage() {
+func svgUsePage() compton.PageElement {
 	p := compton.Page("svg use page")
 
 	p.Append(compton.SvgUse(p, compton.MacOS))
 
+	return p
+}
+
+func writeSvgUsePage() {
+	p := svgUsePage()
+
 	svgUsePath := filepath.Join(os.TempDir(), "svg_use.html")
 	svgUseFile, err := os.Create(svgUsePath)
 	if err != nil {
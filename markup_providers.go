@@ -14,13 +14,13 @@ const (
 	transparentContentMarkup = "{{.Content}}"
 )
 
-type atomsEmbedMarkupProvider struct {
+type embedMarkupProvider struct {
 	efs embed.FS
 	fn  string
 }
 
-func (caemp *atomsEmbedMarkupProvider) GetMarkup() ([]byte, error) {
-	bts, err := caemp.efs.ReadFile(caemp.fn)
+func (emp *embedMarkupProvider) GetMarkup() ([]byte, error) {
+	bts, err := emp.efs.ReadFile(emp.fn)
 	if err != nil {
 		return nil, err
 	}
@@ -28,9 +28,15 @@ func (caemp *atomsEmbedMarkupProvider) GetMarkup() ([]byte, error) {
 }
 
 func atomsEmbedMarkup(ca atom.Atom, efs embed.FS) (atom.Atom, MarkupProvider) {
-	return ca, &atomsEmbedMarkupProvider{
+	return EmbedMarkup(ca, efs, compton_atoms.MarkupName(ca))
+}
+
+// EmbedMarkup allows creating elements with application markup files,
+// that can use {{.Attributes}}, {{.Content}} and {{.Slot:name}} tokens
+func EmbedMarkup(a atom.Atom, efs embed.FS, filename string) (atom.Atom, MarkupProvider) {
+	return a, &embedMarkupProvider{
 		efs: efs,
-		fn:  compton_atoms.MarkupName(ca),
+		fn:  filename,
 	}
 }
 
@@ -8,12 +8,18 @@ import (
 
 const sectionLinksId = "section-links"
 
-const SectionLinksTitle = "&#x2935;" // ARROW POINTING RIGHTWARDS THEN CURVING DOWNWARDS
+const SectionLinksTitle = "\u2935" // ARROW POINTING RIGHTWARDS THEN CURVING DOWNWARDS
 
 type NavLinksElement struct {
 	*BaseElement
 }
 
+func (nle *NavLinksElement) Clone() Element {
+	return &NavLinksElement{
+		BaseElement: nle.BaseElement.cloneBase(),
+	}
+}
+
 func NavLinks(r Registrar) *NavLinksElement {
 	navLinks := &NavLinksElement{
 		BaseElement: NewElement(atomsEmbedMarkup(compton_atoms.NavLinks, DefaultMarkup)),
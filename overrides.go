@@ -0,0 +1,88 @@
+package compton
+
+import (
+	"embed"
+	"github.com/boggydigital/compton/consts/compton_atoms"
+	"golang.org/x/net/html/atom"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+// overrides allow applications to replace built-in components markup and styles
+// without changing component APIs. Overrides are process-wide and keyed by atom
+
+type fileOverride struct {
+	fsys fs.FS
+	name string
+}
+
+func (fo *fileOverride) GetMarkup() ([]byte, error) {
+	return fs.ReadFile(fo.fsys, fo.name)
+}
+
+var (
+	markupOverrides = make(map[atom.Atom]*fileOverride)
+	styleOverrides  = make(map[string]*fileOverride)
+	overridesMtx    = sync.RWMutex{}
+)
+
+// OverrideMarkup replaces markup of elements with atom a with the file name from fsys.
+// Override markup can use the same tokens as the original markup. Components that
+// wrap an inner element (details-summary, labels, iframe-expand-host) write it as {{.Content}}
+func OverrideMarkup(a atom.Atom, fsys fs.FS, name string) {
+	overridesMtx.Lock()
+	defer overridesMtx.Unlock()
+
+	markupOverrides[a] = &fileOverride{fsys: fsys, name: name}
+}
+
+// OverrideStyle replaces style registered for atom a (compton_atoms.StyleName)
+// with the file name from fsys
+func OverrideStyle(a atom.Atom, fsys fs.FS, name string) {
+	overridesMtx.Lock()
+	defer overridesMtx.Unlock()
+
+	styleOverrides[compton_atoms.StyleName(a)] = &fileOverride{fsys: fsys, name: name}
+}
+
+// RemoveOverrides restores built-in markup and style for atom a
+func RemoveOverrides(a atom.Atom) {
+	overridesMtx.Lock()
+	defer overridesMtx.Unlock()
+
+	delete(markupOverrides, a)
+	delete(styleOverrides, compton_atoms.StyleName(a))
+}
+
+func overrideMarkupProvider(a atom.Atom) MarkupProvider {
+	overridesMtx.RLock()
+	defer overridesMtx.RUnlock()
+
+	if mo, ok := markupOverrides[a]; ok {
+		return mo
+	}
+	return nil
+}
+
+// writeOverride writes element with the override markup for its atom, if there is one.
+// Components that write their inner element instead of their own markup (e.g. details-summary)
+// use it, so that overrides apply to them: {{.Content}} of the override markup is the inner element
+func writeOverride(be *BaseElement, w io.Writer) (bool, error) {
+	if overrideMarkupProvider(be.TagName) == nil {
+		return false, nil
+	}
+	return true, be.Write(w)
+}
+
+// readStyle reads style file name from efs, unless it's been overridden
+func readStyle(efs embed.FS, name string) ([]byte, error) {
+	overridesMtx.RLock()
+	so, ok := styleOverrides[name]
+	overridesMtx.RUnlock()
+
+	if ok {
+		return fs.ReadFile(so.fsys, so.name)
+	}
+	return efs.ReadFile(name)
+}
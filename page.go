@@ -1,112 +1,628 @@
 package compton
 
 import (
+	"crypto/sha256"
 	"embed"
 	_ "embed"
+	"errors"
+	"github.com/boggydigital/compton/consts/align"
 	"github.com/boggydigital/compton/consts/attr"
 	"github.com/boggydigital/compton/consts/class"
 	"github.com/boggydigital/compton/consts/color"
 	"github.com/boggydigital/compton/consts/compton_atoms"
+	"github.com/boggydigital/compton/consts/direction"
 	"github.com/boggydigital/compton/consts/font_weight"
 	"golang.org/x/net/html/atom"
 	"io"
+	"maps"
+	"mime"
 	"net/http"
-	"strings"
+	"path"
+	"slices"
 	"sync"
 )
 
+const errorsListName = "errors"
+
 type pageElement struct {
 	BaseElement
 	registry map[string]any
+	styles   map[string][]byte
+	assets   *AssetStore
+	csp      *ContentSecurityPolicy
 	document Element
-	mux      *sync.Mutex
+	// head, requirements and deferrals are cached, so that registrations
+	// don't walk the document, while it's written (see WriteStream)
+	head         Element
+	requirements Element
+	deferrals    Element
+	content      Element
+	// region is the element page children are appended to (see Layout)
+	region Element
+	mux    *sync.Mutex
+	// registryMux guards registrations and content changes made from
+	// other goroutines (e.g. deferred elements producers)
+	registryMux *sync.Mutex
+	streaming   bool
+	// late styles and requirements are written before the deferred
+	// element that registered them, late deferrals are written after it
+	late          []Element
+	lateDeferrals []Element
+	errs          []error
+	pageErrors    []error
+	errorsList    Element
+	development   bool
 }
 
+func (p *pageElement) Clone() Element {
+	clone := &pageElement{
+		registry: maps.Clone(p.registry),
+		styles:   maps.Clone(p.styles),
+		assets:   p.assets,
+		csp:      p.csp,
+		mux:      &sync.Mutex{},
+
+		registryMux: &sync.Mutex{},
+		errs:        slices.Clone(p.errs),
+		pageErrors:  slices.Clone(p.pageErrors),
+		development: p.development,
+	}
+	p.BaseElement.copyTo(&clone.BaseElement)
+	clone.document = clone.Children[0]
+	clone.head = clone.document.GetFirstElementByTagName(atom.Head)
+	clone.requirements = clone.document.GetFirstElementByTagName(compton_atoms.Requirements)
+	clone.deferrals = clone.document.GetFirstElementByTagName(compton_atoms.Deferrals)
+	clone.content = clone.document.GetFirstElementByTagName(compton_atoms.Content)
+	clone.region = clone.content
+	if path, ok := childPath(p.document, p.region); ok {
+		clone.region = childAt(clone.document, path)
+	}
+	if path, ok := childPath(p.document, p.errorsList); ok {
+		clone.errorsList = childAt(clone.document, path)
+	}
+	// registry keeps registered style elements to replace (see UseAssetStore)
+	for name, registered := range clone.registry {
+		if style, ok := registered.(Element); ok {
+			clone.registry[name] = nil
+			if path, ok := childPath(p.document, style); ok {
+				clone.registry[name] = childAt(clone.document, path)
+			}
+		}
+	}
+	rebindRegistrars(p, clone)
+	return clone
+}
+
+// appendStyleClasses writes style for the utility classes used by the page
+// elements, replacing style written before, if any
 func (p *pageElement) appendStyleClasses() {
 	if head := p.document.GetFirstElementByTagName(atom.Head); head != nil {
-		if styleClasses := head.GetElementById("style-classes"); styleClasses == nil {
-			classesStyle := Style(class.StyleClasses())
-			classesStyle.SetId("style-classes")
+		classesStyle := Style(class.StyleClassesFor(collectClassNames(p.document)...))
+		classesStyle.SetId("style-classes")
+		if styleClasses := head.GetElementById("style-classes"); styleClasses != nil {
+			head.ReplaceChild(classesStyle, styleClasses)
+		} else {
 			head.Append(classesStyle)
 		}
 	}
 }
 
+func (p *pageElement) setRegion(region Element) {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.region = region
+}
+
+// childPath returns children indexes from the root to the element
+func childPath(root, el Element) ([]int, bool) {
+	for ii, child := range root.GetChildren() {
+		if child == el {
+			return []int{ii}, true
+		}
+		if path, ok := childPath(child, el); ok {
+			return append([]int{ii}, path...), true
+		}
+	}
+	return nil, false
+}
+
+func childAt(root Element, path []int) Element {
+	el := root
+	for _, ii := range path {
+		el = el.GetChildren()[ii]
+	}
+	return el
+}
+
 func (p *pageElement) Append(children ...Element) {
-	p.document.Append(children...)
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.region.Append(children...)
+}
+
+func (p *pageElement) Prepend(children ...Element) {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.region.Prepend(children...)
+}
+
+func (p *pageElement) InsertBefore(newChild, refChild Element) bool {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	return p.region.InsertBefore(newChild, refChild)
+}
+
+func (p *pageElement) RemoveChild(child Element) bool {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	return p.region.RemoveChild(child)
+}
+
+func (p *pageElement) ReplaceChild(newChild, oldChild Element) bool {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	return p.region.ReplaceChild(newChild, oldChild)
 }
 
 func (p *pageElement) WriteResponse(w http.ResponseWriter) error {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
+	if err := p.prepareResponse(w.Header()); err != nil {
+		return err
+	}
+	if statusCode := p.StatusCode(); statusCode != http.StatusOK {
+		w.WriteHeader(statusCode)
+	}
+	return p.document.Write(w)
+}
+
+// prepareResponse resolves deferred elements, validates the page
+// and sets Content-Security-Policy and Content-Type headers
+func (p *pageElement) prepareResponse(h http.Header) error {
+	p.resolveDeferred()
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	// style classes need to be present to compute policy hashes
+	p.appendStyleClasses()
+	p.setNonce()
+
+	if policy := p.contentSecurityPolicy(); policy != "" {
+		h.Set(p.csp.HeaderName(), policy)
+	}
+	h.Set("Content-Type", "text/html; charset=utf-8")
+	return nil
+}
+
+// WriteStream writes the head and the requirements and flushes them, so that
+// the client can start loading styles and scripts, then writes the content,
+// flushing it as DeferredElement producers complete. Styles and requirements
+// registered by the producers are written before the deferred elements.
+// Inline styles and scripts are allowed by nonce, since they're not known
+// when the policy is sent. Writers that are not http.Flusher get WriteResponse
+func (p *pageElement) WriteStream(w http.ResponseWriter) error {
+	if _, ok := w.(http.Flusher); !ok {
+		return p.WriteResponse(w)
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	p.appendStyleClasses()
+
+	if p.csp.nonce == "" {
+		nonce, err := NewNonce()
+		if err != nil {
+			return err
+		}
+		csp := p.csp
+		p.csp = csp.clone().Nonce(nonce)
+		// nonce is only valid for this response
+		defer func() {
+			p.csp = csp
+			p.setNonce()
+		}()
+	}
+	p.setNonce()
+
 	if policy := p.contentSecurityPolicy(); policy != "" {
-		w.Header().Set("Content-Security-Policy", policy)
+		w.Header().Set(p.csp.HeaderName(), policy)
 	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	return p.Write(w)
+	if statusCode := p.StatusCode(); statusCode != http.StatusOK {
+		w.WriteHeader(statusCode)
+	}
+
+	if body := p.document.GetFirstElementByTagName(atom.Body); body != nil {
+		fe := &flushElement{
+			BaseElement: NewElement(contentMarkup(compton_atoms.Placeholder)),
+			flushed:     p.startStreaming,
+		}
+		body.InsertBefore(fe, p.content)
+		defer body.RemoveChild(fe)
+	}
+	defer p.stopStreaming()
+
+	if err := p.document.Write(w); err != nil {
+		return err
+	}
+	// styles registered by the deferred elements producers
+	return p.Validate()
 }
 
 func (p *pageElement) Write(w io.Writer) error {
+	p.resolveDeferred()
+	if err := p.Validate(); err != nil {
+		return err
+	}
 	p.appendStyleClasses()
 	return p.document.Write(w)
 }
 
+// resolveDeferred waits for all deferred elements (including
+// deferred elements produced by other deferred elements)
+func (p *pageElement) resolveDeferred() {
+	resolved := 0
+	for {
+		deferred := p.document.GetElementsByTagName(compton_atoms.Deferred)
+		if len(deferred) == resolved {
+			return
+		}
+		// producers run concurrently, resolving waits for each of them
+		for _, el := range deferred {
+			if de, ok := el.(deferredElement); ok {
+				de.start()
+			}
+		}
+		for _, el := range deferred {
+			if de, ok := el.(deferredElement); ok {
+				de.resolve()
+			}
+		}
+		resolved = len(deferred)
+	}
+}
+
+// startStreaming is called once the head and requirements are flushed,
+// registrations are collected to be written with the deferred elements from now on
+func (p *pageElement) startStreaming() {
+	p.registryMux.Lock()
+	p.streaming = true
+	p.registryMux.Unlock()
+
+	for _, el := range p.document.GetElementsByTagName(compton_atoms.Deferred) {
+		if de, ok := el.(deferredElement); ok {
+			de.start()
+		}
+	}
+}
+
+func (p *pageElement) stopStreaming() {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.streaming = false
+	p.late, p.lateDeferrals = nil, nil
+}
+
+// streamElements returns styles and requirements registered since the head
+// was written and style classes to write before the deferred element, and
+// deferrals registered since the deferrals were written to write after it
+func (p *pageElement) streamElements(el Element) ([]Element, []Element) {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	if !p.streaming {
+		return nil, nil
+	}
+
+	before, after := p.late, p.lateDeferrals
+	p.late, p.lateDeferrals = nil, nil
+	if sc := class.StyleClassesFor(collectClassNames(el)...); len(sc) > 0 {
+		before = append(before, Style(sc))
+	}
+
+	if p.csp.nonce != "" {
+		for _, se := range slices.Concat(before, []Element{el}, after) {
+			for _, ne := range nonceElements(se) {
+				ne.SetAttribute(attr.Nonce, p.csp.nonce)
+			}
+		}
+	}
+
+	return before, after
+}
+
 func (p *pageElement) RegisterStyles(efs embed.FS, names ...string) {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.registerStyles(efs, names...)
+}
+
+func (p *pageElement) registerStyles(efs embed.FS, names ...string) {
 	for _, name := range names {
 		if _, ok := p.registry[name]; !ok {
 			p.registry[name] = nil
-			if content, err := efs.ReadFile(name); err == nil {
+			if content, err := readStyle(efs, name); err == nil {
 				if len(content) > 0 {
-					if head := p.document.GetFirstElementByTagName(atom.Head); head != nil {
-						head.Append(Style(content))
+					if p.streaming {
+						p.styles[name] = content
+						p.late = append(p.late, p.styleElement(name, content))
+					} else {
+						p.styles[name] = content
+						p.head.Append(p.styleElement(name, content))
 					}
 				}
 			} else {
-				panic(err)
+				p.errs = append(p.errs, ErrRegistration(name, err))
 			}
 		}
 	}
 }
 
+func (p *pageElement) registrationError(err error) {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.errs = append(p.errs, err)
+}
+
+// heldRegistrar registers with the page, while the caller holds registryMux
+// (e.g. for the components created by Error)
+type heldRegistrar struct {
+	p *pageElement
+}
+
+func (hr heldRegistrar) RegisterStyles(efs embed.FS, names ...string) {
+	hr.p.registerStyles(efs, names...)
+}
+
+func (hr heldRegistrar) RegisterRequirements(name string, elements ...Element) {
+	hr.p.registerRequirements(name, elements...)
+}
+
+func (hr heldRegistrar) RegisterDeferrals(name string, elements ...Element) {
+	hr.p.registerDeferrals(name, elements...)
+}
+
+func (hr heldRegistrar) registrationError(err error) {
+	hr.p.errs = append(hr.p.errs, err)
+}
+
+// Validate returns registration errors (e.g. missing styles),
+// pages with registration errors are not written
+func (p *pageElement) Validate() error {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	return errors.Join(p.errs...)
+}
+
 func (p *pageElement) RegisterRequirements(name string, elements ...Element) {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.registerRequirements(name, elements...)
+}
+
+func (p *pageElement) registerRequirements(name string, elements ...Element) {
 	if _, ok := p.registry[name]; !ok {
 		p.registry[name] = nil
-		if body := p.document.GetFirstElementByTagName(atom.Body); body != nil {
-			if req := body.GetFirstElementByTagName(compton_atoms.Requirements); req != nil {
-				req.Append(elements...)
-			}
+		if p.streaming {
+			p.late = append(p.late, p.scriptElements(name, elements)...)
+		} else {
+			p.requirements.Append(p.scriptElements(name, elements)...)
 		}
 	}
 }
 
 func (p *pageElement) RegisterDeferrals(name string, elements ...Element) {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.registerDeferrals(name, elements...)
+}
+
+func (p *pageElement) registerDeferrals(name string, elements ...Element) {
 	if _, ok := p.registry[name]; !ok {
 		p.registry[name] = nil
-		if body := p.document.GetFirstElementByTagName(atom.Body); body != nil {
-			if def := body.GetFirstElementByTagName(compton_atoms.Deferrals); def != nil {
-				def.Append(elements...)
-			}
+		if p.streaming {
+			p.lateDeferrals = append(p.lateDeferrals, p.scriptElements(name, elements)...)
+		} else {
+			p.deferrals.Append(p.scriptElements(name, elements)...)
 		}
 	}
 }
 
-func (p *pageElement) Error(err error) PageElement {
-	msg := Fspan(p, err.Error()).BackgroundColor(color.Red).FontWeight(font_weight.Bolder)
-	msg.AddClass("_compton_error_message")
-	p.RegisterRequirements(err.Error(), FICenter(p, msg))
+// Error lists errors at the top of the page (or in the ErrorPage content).
+// Page is written with the status code of the first error
+func (p *pageElement) Error(errs ...error) PageElement {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	for _, err := range errs {
+		if err != nil {
+			p.pageErrors = append(p.pageErrors, err)
+		}
+	}
+	if len(p.pageErrors) == 0 {
+		return p
+	}
+	if p.errorsList == nil {
+		p.errorsList = FlexItems(heldRegistrar{p}, direction.Column).AlignItems(align.Center)
+		p.registerRequirements(errorsListName, p.errorsList)
+	}
+	p.appendErrorMessages()
+	return p
+}
+
+// appendErrorMessages is called with registryMux held
+func (p *pageElement) appendErrorMessages() {
+	for _, child := range slices.Clone(p.errorsList.GetChildren()) {
+		p.errorsList.RemoveChild(child)
+	}
+	for _, err := range p.pageErrors {
+		msg := Fspan(heldRegistrar{p}, errorMessage(err, p.development)).BackgroundColor(color.Red).FontWeight(font_weight.Bolder)
+		msg.AddClass("_compton_error_message")
+		p.errorsList.Append(msg)
+	}
+}
+
+// SetDevelopmentMode shows messages of all errors, including server errors,
+// that are otherwise shown with the status text only
+func (p *pageElement) SetDevelopmentMode(development bool) PageElement {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.development = development
+	if p.errorsList != nil {
+		p.appendErrorMessages()
+	}
+	return p
+}
+
+// StatusCode returns status code of the first page error or http.StatusOK
+func (p *pageElement) StatusCode() int {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	if len(p.pageErrors) > 0 {
+		return StatusCode(p.pageErrors[0])
+	}
+	return http.StatusOK
+}
+
+// UseAssetStore switches page to write registered styles and scripts as
+// external, content-hashed assets, served by the AssetStore. Styles and scripts
+// registered before are converted as well. Pages are written with inline
+// styles and scripts by default, producing single-file output
+func (p *pageElement) UseAssetStore(as *AssetStore) PageElement {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	p.assets = as
+
+	if head := p.document.GetFirstElementByTagName(atom.Head); head != nil {
+		for name, content := range p.styles {
+			if style, ok := p.registry[name].(Element); ok {
+				head.ReplaceChild(p.styleElement(name, content), style)
+			}
+		}
+	}
+
+	for _, container := range []atom.Atom{compton_atoms.Requirements, compton_atoms.Deferrals} {
+		if ce := p.document.GetFirstElementByTagName(container); ce != nil {
+			for _, child := range slices.Clone(ce.GetChildren()) {
+				if se, ok := child.(*ScriptElement); ok {
+					if external := p.externalScript(compton_atoms.Atos(container), se); external != se {
+						ce.ReplaceChild(external, child)
+					}
+				}
+			}
+		}
+	}
+
 	return p
 }
 
+// styleElement creates inline style or, with an AssetStore, stylesheet link
+// and records it in the registry
+func (p *pageElement) styleElement(name string, content []byte) Element {
+	var style Element
+	if p.assets != nil {
+		style = Link(map[string]string{
+			attr.Rel:       attr.Stylesheet,
+			attr.Href:      p.assets.Add(name, textCssContentType, content),
+			attr.Integrity: integrity(content),
+		})
+	} else {
+		style = Style(content)
+	}
+	p.registry[name] = style
+	return style
+}
+
+// scriptElements converts script elements to external scripts
+// when the page uses AssetStore
+func (p *pageElement) scriptElements(name string, elements []Element) []Element {
+	if p.assets == nil {
+		return elements
+	}
+	converted := make([]Element, 0, len(elements))
+	for _, el := range elements {
+		if se, ok := el.(*ScriptElement); ok {
+			converted = append(converted, p.externalScript(name, se))
+		} else {
+			converted = append(converted, el)
+		}
+	}
+	return converted
+}
+
+// externalScript returns external script for classic and module scripts,
+// other script types (e.g. speculationrules, JSON data) stay inline
+func (p *pageElement) externalScript(name string, se *ScriptElement) Element {
+	switch se.GetAttribute(attr.Type) {
+	case "", "module", "text/javascript":
+		// convert below
+	default:
+		return se
+	}
+
+	if path.Ext(name) != ".js" {
+		name += ".js"
+	}
+
+	external := ScriptSrc(p.assets.Add(name, textJavascriptContentType, se.code))
+	for _, an := range se.Attributes.names {
+		external.SetAttribute(an, se.GetAttribute(an))
+	}
+	external.SetAttribute(attr.Integrity, integrity(se.code))
+	external.AddClass(se.GetClassNames()...)
+	return external
+}
+
+func integrity(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256-" + b64.EncodeToString(sum[:])
+}
+
 func (p *pageElement) IsRegistered(name string) bool {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
 	_, ok := p.registry[name]
 	return ok
 }
 
-func (p *pageElement) AppendManifest() PageElement {
+// RegisteredNames returns names of registered styles, requirements and deferrals
+func (p *pageElement) RegisteredNames() []string {
+	p.registryMux.Lock()
+	defer p.registryMux.Unlock()
+
+	return slices.Sorted(maps.Keys(p.registry))
+}
+
+// AppendManifest links web app manifest at href (attr.ManifestJson, when empty).
+// When manifest is provided, manifest theme color is set as page theme-color
+func (p *pageElement) AppendManifest(href string, wam *WebAppManifest) PageElement {
+	if href == "" {
+		href = attr.ManifestJson
+	}
 	if head := p.document.GetFirstElementByTagName(atom.Head); head != nil {
 		head.Append(Link(map[string]string{
-			attr.Rel: attr.Manifest, attr.Href: attr.ManifestJson,
+			attr.Rel: attr.Manifest, attr.Href: href,
 		}))
 		head.Append(Meta(map[string]string{
 			attr.Name: attr.MobileWebAppCapable, attr.Content: attr.Yes,
@@ -115,16 +631,26 @@ func (p *pageElement) AppendManifest() PageElement {
 			attr.Name: attr.AppleMobileWebAppStatusBarStyle, attr.Content: attr.BlackTranslucent,
 		}))
 	}
+	if wam != nil && wam.ThemeColor != color.Unset {
+		p.SetThemeColor(wam.ThemeColor.LightValue(), wam.ThemeColor.DarkValue())
+	}
 	return p
 }
 
-func (p *pageElement) AppendIcon() PageElement {
+// AppendIcon links icon at href (attr.IconPng, when empty)
+func (p *pageElement) AppendIcon(href string) PageElement {
+	if href == "" {
+		href = attr.IconPng
+	}
 	if head := p.document.GetFirstElementByTagName(atom.Head); head != nil {
-		head.Append(Link(map[string]string{
+		icon := map[string]string{
 			attr.Rel:  attr.Icon,
-			attr.Href: attr.IconPng,
-			attr.Type: attr.ImagePng,
-		}))
+			attr.Href: href,
+		}
+		if iconType := mime.TypeByExtension(path.Ext(href)); iconType != "" {
+			icon[attr.Type] = iconType
+		}
+		head.Append(Link(icon))
 	}
 	return p
 }
@@ -151,6 +677,12 @@ func (p *pageElement) SetAttribute(name, val string) {
 	}
 }
 
+func (p *pageElement) RemoveAttribute(name string) {
+	if html := p.document.GetFirstElementByTagName(atom.Html); html != nil {
+		html.RemoveAttribute(name)
+	}
+}
+
 func (p *pageElement) SetBodyId(id string) PageElement {
 	if body := p.document.GetFirstElementByTagName(atom.Body); body != nil {
 		body.SetId(id)
@@ -158,17 +690,63 @@ func (p *pageElement) SetBodyId(id string) PageElement {
 	return p
 }
 
+// SetContentSecurityPolicy replaces default policy, that only
+// allows inline scripts and styles by their hashes
+func (p *pageElement) SetContentSecurityPolicy(csp *ContentSecurityPolicy) PageElement {
+	p.csp = csp
+	return p
+}
+
 func (p *pageElement) contentSecurityPolicy() string {
-	if scripts := p.document.GetElementsByTagName(atom.Script); len(scripts) > 0 {
-		digests := make([]string, 0, len(scripts))
-		for _, s := range scripts {
-			if se, ok := s.(*ScriptElement); ok {
-				digests = append(digests, "'"+se.Sha256()+"'")
-			}
+	var scriptSources, styleSources []string
+
+	for _, s := range p.document.GetElementsByTagName(atom.Script) {
+		if se, ok := s.(*ScriptElement); ok {
+			scriptSources = append(scriptSources, "'"+se.Sha256()+"'")
+		}
+	}
+	for _, s := range p.document.GetElementsByTagName(atom.Style) {
+		if se, ok := s.(*StyleElement); ok {
+			styleSources = append(styleSources, "'"+se.Sha256()+"'")
+		}
+	}
+
+	if p.assets != nil {
+		scriptSources = append(scriptSources, p.assets.cspSource())
+		styleSources = append(styleSources, p.assets.cspSource())
+	}
+
+	return p.csp.build(uniqueSources(scriptSources), uniqueSources(styleSources))
+}
+
+// setNonce sets policy nonce on every script, style and stylesheet link,
+// or removes nonce set for the previous write, when policy has no nonce
+func (p *pageElement) setNonce() {
+	for _, el := range nonceElements(p.document) {
+		if p.csp.nonce == "" {
+			el.RemoveAttribute(attr.Nonce)
+		} else {
+			el.SetAttribute(attr.Nonce, p.csp.nonce)
 		}
-		return "script-src " + strings.Join(digests, " ")
 	}
-	return ""
+}
+
+// nonceElements returns scripts, styles and stylesheet links
+// of the element and its descendants
+func nonceElements(root Element) []Element {
+	var elements []Element
+	for _, el := range append([]Element{root}, root.GetElementsByTagName(atom.Link)...) {
+		if el.GetTagName() == atom.Link && el.GetAttribute(attr.Rel) == attr.Stylesheet {
+			elements = append(elements, el)
+		}
+	}
+	for _, tag := range []atom.Atom{atom.Script, atom.Style} {
+		if root.GetTagName() == tag {
+			elements = append(elements, root)
+		}
+		elements = append(elements, root.GetElementsByTagName(tag)...)
+	}
+	return elements
 }
 
 func (p *pageElement) appendMetaCharset() {
@@ -215,17 +793,28 @@ func Page(title string) PageElement {
 			TagName: compton_atoms.Page,
 		},
 		registry: make(map[string]any),
+		styles:   make(map[string][]byte),
+		csp:      NewContentSecurityPolicy(),
 		mux:      &sync.Mutex{},
+
+		registryMux: &sync.Mutex{},
 	}
 
 	page.document = Document()
+	page.BaseElement.Append(page.document)
 	html := Html("en")
 	page.document.Append(Doctype(), html)
 
 	body := Body()
-	html.Append(Head(), body)
-
-	body.Append(Requirements(), Content(), Deferrals())
+	page.head = Head()
+	html.Append(page.head, body)
+
+	// page content is appended to the body content, between requirements and deferrals
+	page.requirements = Requirements()
+	page.content = Content()
+	page.region = page.content
+	page.deferrals = Deferrals()
+	body.Append(page.requirements, page.content, page.deferrals)
 
 	page.appendMetaCharset()
 	page.appendTitle(title)
@@ -8,14 +8,32 @@ type PageElement interface {
 	Element
 	Registrar
 
-	Error(err error) PageElement
+	Error(errs ...error) PageElement
+	SetDevelopmentMode(development bool) PageElement
+	StatusCode() int
 
 	SetBodyId(id string) PageElement
 	SetBodyAttribute(name, val string)
 
-	AppendManifest() PageElement
-	AppendIcon() PageElement
+	RegisteredNames() []string
+
+	AppendManifest(href string, wam *WebAppManifest) PageElement
+	AppendIcon(href string) PageElement
 	AppendSpeculationRules(hrefMatches ...string)
 
+	SetDescription(description string) PageElement
+	SetCanonical(href string) PageElement
+	SetRobots(directives ...string) PageElement
+	SetThemeColor(light, dark string) PageElement
+	SetOpenGraph(og *OpenGraph) PageElement
+	SetTwitterCard(tc *TwitterCard) PageElement
+	AppendJsonLd(data any) error
+
+	UseAssetStore(as *AssetStore) PageElement
+	SetContentSecurityPolicy(csp *ContentSecurityPolicy) PageElement
+
+	Validate() error
 	WriteResponse(w http.ResponseWriter) error
+	WriteBufferedResponse(w http.ResponseWriter, r *http.Request) error
+	WriteStream(w http.ResponseWriter) error
 }
@@ -0,0 +1,165 @@
+package compton
+
+import (
+	"encoding/json"
+	"github.com/boggydigital/compton/consts/attr"
+	"golang.org/x/net/html/atom"
+	"strings"
+)
+
+const (
+	prefersColorSchemeLight = "(prefers-color-scheme: light)"
+	prefersColorSchemeDark  = "(prefers-color-scheme: dark)"
+)
+
+// OpenGraph properties (https://ogp.me), empty values are not written
+type OpenGraph struct {
+	Type        string
+	Title       string
+	Description string
+	Url         string
+	Image       string
+	ImageAlt    string
+	SiteName    string
+	Locale      string
+}
+
+func (og *OpenGraph) properties() [][2]string {
+	return [][2]string{
+		{"og:type", og.Type},
+		{"og:title", og.Title},
+		{"og:description", og.Description},
+		{"og:url", og.Url},
+		{"og:image", og.Image},
+		{"og:image:alt", og.ImageAlt},
+		{"og:site_name", og.SiteName},
+		{"og:locale", og.Locale},
+	}
+}
+
+// TwitterCard properties (https://developer.x.com/en/docs/x-for-websites/cards/overview/markup),
+// empty values are not written
+type TwitterCard struct {
+	Card        string
+	Site        string
+	Creator     string
+	Title       string
+	Description string
+	Image       string
+	ImageAlt    string
+}
+
+func (tc *TwitterCard) names() [][2]string {
+	return [][2]string{
+		{"twitter:card", tc.Card},
+		{"twitter:site", tc.Site},
+		{"twitter:creator", tc.Creator},
+		{"twitter:title", tc.Title},
+		{"twitter:description", tc.Description},
+		{"twitter:image", tc.Image},
+		{"twitter:image:alt", tc.ImageAlt},
+	}
+}
+
+// setHeadElement replaces head element matching selector with the
+// new element, or appends it to the head. Nil element removes existing element
+func (p *pageElement) setHeadElement(selector string, el Element) {
+	head := p.document.GetFirstElementByTagName(atom.Head)
+	if head == nil {
+		return
+	}
+	existing := head.QuerySelector(selector)
+	switch {
+	case existing != nil && el != nil:
+		head.ReplaceChild(el, existing)
+	case existing != nil:
+		head.RemoveChild(existing)
+	case el != nil:
+		head.Append(el)
+	}
+}
+
+// setMeta sets meta content for the name (or property) key,
+// empty content removes the meta
+func (p *pageElement) setMeta(keyAttr, key, content string) {
+	var meta Element
+	if content != "" {
+		meta = Meta(map[string]string{keyAttr: key, attr.Content: content})
+	}
+	p.setHeadElement("meta["+keyAttr+"=\""+key+"\"]", meta)
+}
+
+func (p *pageElement) SetDescription(description string) PageElement {
+	p.setMeta(attr.Name, attr.Description, description)
+	return p
+}
+
+func (p *pageElement) SetCanonical(href string) PageElement {
+	var link Element
+	if href != "" {
+		link = Link(map[string]string{attr.Rel: attr.Canonical, attr.Href: href})
+	}
+	p.setHeadElement("link[rel="+attr.Canonical+"]", link)
+	return p
+}
+
+// SetRobots sets robots directives, e.g. attr.NoIndex, attr.NoFollow
+func (p *pageElement) SetRobots(directives ...string) PageElement {
+	p.setMeta(attr.Name, attr.Robots, strings.Join(directives, ", "))
+	return p
+}
+
+// SetThemeColor sets theme-color for light and dark color schemes.
+// When dark is empty or the same as light, a single theme-color is set
+func (p *pageElement) SetThemeColor(light, dark string) PageElement {
+	head := p.document.GetFirstElementByTagName(atom.Head)
+	if head == nil {
+		return p
+	}
+	for _, tc := range head.QuerySelectorAll("meta[name=" + attr.ThemeColor + "]") {
+		head.RemoveChild(tc)
+	}
+
+	if dark == "" || dark == light {
+		p.setMeta(attr.Name, attr.ThemeColor, light)
+		return p
+	}
+
+	for _, mc := range [][2]string{{prefersColorSchemeLight, light}, {prefersColorSchemeDark, dark}} {
+		head.Append(Meta(map[string]string{
+			attr.Name:    attr.ThemeColor,
+			attr.Content: mc[1],
+			attr.Media:   mc[0],
+		}))
+	}
+	return p
+}
+
+func (p *pageElement) SetOpenGraph(og *OpenGraph) PageElement {
+	for _, property := range og.properties() {
+		p.setMeta(attr.Property, property[0], property[1])
+	}
+	return p
+}
+
+func (p *pageElement) SetTwitterCard(tc *TwitterCard) PageElement {
+	for _, name := range tc.names() {
+		p.setMeta(attr.Name, name[0], name[1])
+	}
+	return p
+}
+
+// AppendJsonLd appends JSON-LD structured data block (https://json-ld.org) to the head.
+// Blocks are script elements, so they're included in the page Content-Security-Policy
+func (p *pageElement) AppendJsonLd(data any) error {
+	bts, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if head := p.document.GetFirstElementByTagName(atom.Head); head != nil {
+		jsonLd := Script(bts)
+		jsonLd.SetAttribute(attr.Type, attr.ApplicationLdJson)
+		head.Append(jsonLd)
+	}
+	return nil
+}
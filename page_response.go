@@ -0,0 +1,118 @@
+package compton
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// buffered response ETag uses that many bytes of content sha256
+const etagHashLength = 16
+
+// WriteBufferedResponse writes the page into a buffer first, so that errors
+// get http.StatusInternalServerError response, instead of a truncated page.
+// Buffered response has strong ETag (matching If-None-Match requests get
+// http.StatusNotModified) and Content-Length, and is gzip compressed, when
+// the client accepts that. HEAD requests get the headers only.
+// Pages with the policy nonce are different for every request (see
+// ContentSecurityPolicy.Nonce), so they're written without ETag: cached
+// content would have the nonce of the previous response
+func (p *pageElement) WriteBufferedResponse(w http.ResponseWriter, r *http.Request) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	header := make(http.Header)
+	buf := &bytes.Buffer{}
+
+	if err := p.prepareResponse(header); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	if err := p.document.Write(buf); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+
+	body := buf.Bytes()
+	sum := sha256.Sum256(body)
+	etag := hex.EncodeToString(sum[:etagHashLength])
+
+	if acceptsGzip(r) {
+		gzBuf := &bytes.Buffer{}
+		gzw := gzip.NewWriter(gzBuf)
+		if _, err := gzw.Write(body); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return err
+		}
+		if err := gzw.Close(); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return err
+		}
+		body = gzBuf.Bytes()
+		// compressed representation needs its own strong ETag
+		etag += "-gzip"
+		header.Set("Content-Encoding", "gzip")
+	}
+
+	etag = strconv.Quote(etag)
+	if p.csp.nonce == "" {
+		header.Set("ETag", etag)
+	}
+	header.Set("Vary", "Accept-Encoding")
+	maps.Copy(w.Header(), header)
+
+	statusCode := p.StatusCode()
+	if statusCode == http.StatusOK && p.csp.nonce == "" && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(statusCode)
+
+	if r.Method == http.MethodHead {
+		return nil
+	}
+	_, err := w.Write(body)
+	return err
+}
+
+// acceptsGzip checks Accept-Encoding for gzip with non-zero quality.
+// Explicit gzip entry takes precedence over the * wildcard
+func acceptsGzip(r *http.Request) bool {
+	wildcard := false
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(encoding, ";")
+		name = strings.TrimSpace(name)
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		accepted := true
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if qv, err := strconv.ParseFloat(q, 64); err == nil && qv == 0 {
+				accepted = false
+			}
+		}
+		if name == "gzip" {
+			return accepted
+		}
+		wildcard = accepted
+	}
+	return wildcard
+}
+
+// etagMatches uses weak comparison, as required for If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+package compton
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		want           bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"GZIP", false},
+		{"br, gzip", true},
+		{"gzip, deflate, br", true},
+		{"br", false},
+		{"gzip;q=0.5", true},
+		{"gzip; q=0.5", true},
+		{"gzip;q=0", false},
+		{"gzip;q=0.0", false},
+		{"*", true},
+		{"*;q=0", false},
+		{"br, *", true},
+		{"gzip;q=0, *", false},
+		{"*, gzip;q=0", false},
+		{"gzip, *;q=0", true},
+		{"*;q=0, gzip", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			if got := acceptsGzip(r); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{"", false},
+		{`"a"`, true},
+		{`W/"a"`, true},
+		{`"b"`, false},
+		{`"b", "a"`, true},
+		{`"b",W/"a"`, true},
+		{"*", true},
+		{"a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ifNoneMatch, func(t *testing.T) {
+			if got := etagMatches(tt.ifNoneMatch, `"a"`); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func writeBufferedResponse(t *testing.T, p PageElement, r *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	if err := p.WriteBufferedResponse(rec, r); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestWriteBufferedResponse(t *testing.T) {
+	page := func() PageElement {
+		p := Page("buffered")
+		p.Append(DivText("content"))
+		return p
+	}
+
+	get := httptest.NewRequest(http.MethodGet, "/", nil)
+	plain := writeBufferedResponse(t, page(), get)
+	etag := plain.Header().Get("ETag")
+
+	gzGet := httptest.NewRequest(http.MethodGet, "/", nil)
+	gzGet.Header.Set("Accept-Encoding", "gzip")
+	gzEtag := writeBufferedResponse(t, page(), gzGet).Header().Get("ETag")
+
+	if etag == "" || gzEtag == "" || etag == gzEtag {
+		t.Fatalf("got ETag %q and gzip ETag %q, want different non-empty values", etag, gzEtag)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		ifNoneMatch    string
+		wantStatus     int
+		wantEncoding   string
+		wantEtag       string
+		wantBody       bool
+	}{
+		{"get", http.MethodGet, "", "", http.StatusOK, "", etag, true},
+		{"get gzip", http.MethodGet, "gzip", "", http.StatusOK, "gzip", gzEtag, true},
+		{"get refused gzip", http.MethodGet, "gzip;q=0, *", "", http.StatusOK, "", etag, true},
+		{"head", http.MethodHead, "", "", http.StatusOK, "", etag, false},
+		{"head gzip", http.MethodHead, "gzip", "", http.StatusOK, "gzip", gzEtag, false},
+		{"if-none-match", http.MethodGet, "", etag, http.StatusNotModified, "", etag, false},
+		{"if-none-match weak", http.MethodGet, "", "W/" + etag, http.StatusNotModified, "", etag, false},
+		{"if-none-match list", http.MethodGet, "", `"other", ` + etag, http.StatusNotModified, "", etag, false},
+		{"if-none-match gzip", http.MethodGet, "gzip", gzEtag, http.StatusNotModified, "gzip", gzEtag, false},
+		{"if-none-match other encoding", http.MethodGet, "gzip", etag, http.StatusOK, "gzip", gzEtag, true},
+		{"if-none-match stale", http.MethodGet, "", `"stale"`, http.StatusOK, "", etag, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			rec := writeBufferedResponse(t, page(), r)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("got Content-Encoding %q, want %q", got, tt.wantEncoding)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.wantEtag {
+				t.Errorf("got ETag %q, want %q", got, tt.wantEtag)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("got Vary %q, want Accept-Encoding", got)
+			}
+
+			if !tt.wantBody {
+				if rec.Body.Len() > 0 {
+					t.Errorf("got %d bytes body, want none", rec.Body.Len())
+				}
+				return
+			}
+
+			if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(rec.Body.Len()) {
+				t.Errorf("got Content-Length %s, want %d", got, rec.Body.Len())
+			}
+
+			body := rec.Body.Bytes()
+			if tt.wantEncoding == "gzip" {
+				gzr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if body, err = io.ReadAll(gzr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !bytes.Equal(body, plain.Body.Bytes()) {
+				t.Error("body is different from the plain response body")
+			}
+		})
+	}
+}
+
+func TestWriteBufferedResponseHeadContentLength(t *testing.T) {
+	p := Page("head")
+	get := writeBufferedResponse(t, p.Clone().(PageElement), httptest.NewRequest(http.MethodGet, "/", nil))
+	head := writeBufferedResponse(t, p.Clone().(PageElement), httptest.NewRequest(http.MethodHead, "/", nil))
+
+	if got, want := head.Header().Get("Content-Length"), get.Header().Get("Content-Length"); got != want {
+		t.Errorf("got HEAD Content-Length %s, want %s", got, want)
+	}
+}
+
+func TestWriteBufferedResponseNonce(t *testing.T) {
+	p := Page("nonce").SetContentSecurityPolicy(NewContentSecurityPolicy().DefaultSrc(CspSelf).Nonce("n0nce"))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", "*")
+
+	rec := writeBufferedResponse(t, p, r)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("ETag"); got != "" {
+		t.Errorf("got ETag %q, want none", got)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("nonce='n0nce'")) {
+		t.Error("body doesn't have the nonce")
+	}
+}
+
+func TestWriteBufferedResponseErrorStatus(t *testing.T) {
+	p := ErrorPage(ErrNotFound(nil))
+	etag := writeBufferedResponse(t, p.Clone().(PageElement), httptest.NewRequest(http.MethodGet, "/", nil)).Header().Get("ETag")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+
+	rec := writeBufferedResponse(t, p.Clone().(PageElement), r)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec.Body.Len() == 0 {
+		t.Error("got empty body")
+	}
+}
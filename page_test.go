@@ -0,0 +1,190 @@
+package compton
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"github.com/boggydigital/compton/consts/align"
+	"github.com/boggydigital/compton/consts/direction"
+	"github.com/boggydigital/compton/consts/input_types"
+	"github.com/boggydigital/compton/consts/size"
+	"golang.org/x/net/html/atom"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func goldenPage() PageElement {
+	p := Page("golden")
+
+	stack := FlexItems(p, direction.Column).RowGap(size.Small).AlignContent(align.Center)
+
+	link := AtomicElement(atom.A)
+	link.SetAttribute("title", "title")
+	link.SetAttribute("href", "/path")
+	link.SetAttribute("data-z", "z")
+	link.SetAttribute("data-a", "a")
+	link.AddClass("z-class", "a-class", "m-class")
+	link.Append(Text("link"))
+
+	grid := GridItems(p).JustifyContent(align.Start)
+	grid.Append(DivText("one"), DivText("two"))
+
+	stack.Append(link, grid)
+	p.Append(stack)
+
+	return p
+}
+
+// TestPageGolden checks that two writes of the same page are identical
+// and match testdata/page.golden. Run with -update to update the file
+func TestPageGolden(t *testing.T) {
+	goldenPath := filepath.Join("testdata", "page.golden")
+
+	var renders [2][]byte
+	for ii := range renders {
+		buf := new(bytes.Buffer)
+		if err := goldenPage().Write(buf); err != nil {
+			t.Fatal(err)
+		}
+		renders[ii] = buf.Bytes()
+	}
+
+	if !bytes.Equal(renders[0], renders[1]) {
+		t.Fatal("page writes are not identical")
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenPath, renders[0], 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	golden, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(renders[0], golden) {
+		t.Errorf("page doesn't match %s, run with -update if the change is expected", goldenPath)
+	}
+}
+
+func TestPageCloneUseAssetStore(t *testing.T) {
+	p := Page("clone")
+	p.Append(FlexItems(p, direction.Row))
+
+	clone := p.Clone().(PageElement)
+	clone.UseAssetStore(NewAssetStore("/assets/"))
+
+	// style classes are written inline with AssetStore as well
+	tests := []struct {
+		name        string
+		page        PageElement
+		styles      int
+		stylesheets int
+	}{
+		{"original", p, 5, 0},
+		{"clone", clone, 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := tt.page.Write(buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := bytes.Count(buf.Bytes(), []byte("<style ")); got != tt.styles {
+				t.Errorf("got %d styles, want %d", got, tt.styles)
+			}
+			if got := bytes.Count(buf.Bytes(), []byte("rel='stylesheet'")); got != tt.stylesheets {
+				t.Errorf("got %d stylesheets, want %d", got, tt.stylesheets)
+			}
+		})
+	}
+}
+
+func TestPageCloneRegistrar(t *testing.T) {
+	p := Page("clone")
+	input := Input(p, input_types.Text)
+	input.SetId("input")
+	p.Append(input)
+
+	clone := p.Clone().(PageElement)
+	cloneInput, ok := clone.GetElementById("input").(*InputElement)
+	if !ok {
+		t.Fatal("clone input is not an InputElement")
+	}
+	cloneInput.SetDatalist(map[string]string{"value": "title"}, "list")
+
+	tests := []struct {
+		name     string
+		page     PageElement
+		datalist bool
+	}{
+		{"original", p, false},
+		{"clone", clone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := tt.page.Write(buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := bytes.Contains(buf.Bytes(), []byte("<datalist id='list'>")); got != tt.datalist {
+				t.Errorf("got datalist %v, want %v", got, tt.datalist)
+			}
+		})
+	}
+}
+
+func TestPageStyleClassesUserData(t *testing.T) {
+	p := Page("labels")
+	p.Append(Labels(p, FormattedLabel{Title: "fg-_0}body{display:none"}))
+
+	buf := new(bytes.Buffer)
+	if err := p.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	// label title is written as a class name and text, not as a style
+	if bytes.Contains(buf.Bytes(), []byte("{--fg:#0}")) {
+		t.Error("label title class is written into the style classes")
+	}
+}
+
+func TestPageConcurrentChanges(t *testing.T) {
+	p := Page("concurrent")
+
+	wg := &sync.WaitGroup{}
+	for ii := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Append(FlexItems(p, direction.Row))
+			p.Error(ErrNotFound(errors.New("not found")))
+			p.SetDevelopmentMode(ii%2 == 0)
+			_ = p.RegisteredNames()
+			_ = p.StatusCode()
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.UseAssetStore(NewAssetStore("/assets/"))
+	}()
+	wg.Wait()
+
+	if got := p.StatusCode(); got != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", got, http.StatusNotFound)
+	}
+	if err := p.Write(new(bytes.Buffer)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,128 @@
+package compton
+
+import (
+	"embed"
+	"slices"
+	"sync"
+)
+
+type registration struct {
+	efs        embed.FS
+	styles     []string
+	name       string
+	elements   []Element
+	isDeferral bool
+}
+
+// recordingRegistrar records registrations of a parallel builder, to be replayed
+// with the target Registrar, once the builder is done. Registrations made after that
+// (e.g. by elements that keep the Registrar) are forwarded to the target Registrar
+type recordingRegistrar struct {
+	target        Registrar
+	registrations []registration
+	replayed      bool
+	mtx           sync.Mutex
+}
+
+func (rr *recordingRegistrar) RegisterStyles(efs embed.FS, names ...string) {
+	rr.mtx.Lock()
+	defer rr.mtx.Unlock()
+
+	if rr.replayed {
+		rr.target.RegisterStyles(efs, names...)
+		return
+	}
+	rr.registrations = append(rr.registrations, registration{efs: efs, styles: names})
+}
+
+func (rr *recordingRegistrar) RegisterRequirements(name string, elements ...Element) {
+	rr.mtx.Lock()
+	defer rr.mtx.Unlock()
+
+	if rr.replayed {
+		rr.target.RegisterRequirements(name, elements...)
+		return
+	}
+	rr.registrations = append(rr.registrations, registration{name: name, elements: elements})
+}
+
+func (rr *recordingRegistrar) RegisterDeferrals(name string, elements ...Element) {
+	rr.mtx.Lock()
+	defer rr.mtx.Unlock()
+
+	if rr.replayed {
+		rr.target.RegisterDeferrals(name, elements...)
+		return
+	}
+	rr.registrations = append(rr.registrations, registration{name: name, elements: elements, isDeferral: true})
+}
+
+// IsRegistered checks recorded registrations of this builder and the target Registrar
+func (rr *recordingRegistrar) IsRegistered(name string) bool {
+	rr.mtx.Lock()
+	for _, reg := range rr.registrations {
+		if reg.name == name || slices.Contains(reg.styles, name) {
+			rr.mtx.Unlock()
+			return true
+		}
+	}
+	rr.mtx.Unlock()
+
+	rc, ok := rr.target.(registryChecker)
+	return ok && rc.IsRegistered(name)
+}
+
+func (rr *recordingRegistrar) registrationError(err error) {
+	reportRegistrationError(rr.target, err)
+}
+
+func (rr *recordingRegistrar) streamElements(el Element) ([]Element, []Element) {
+	if sr, ok := rr.target.(streamRegistrar); ok {
+		return sr.streamElements(el)
+	}
+	return nil, nil
+}
+
+func (rr *recordingRegistrar) replay() {
+	rr.mtx.Lock()
+	defer rr.mtx.Unlock()
+
+	for _, reg := range rr.registrations {
+		switch {
+		case len(reg.styles) > 0:
+			rr.target.RegisterStyles(reg.efs, reg.styles...)
+		case reg.isDeferral:
+			rr.target.RegisterDeferrals(reg.name, reg.elements...)
+		default:
+			rr.target.RegisterRequirements(reg.name, reg.elements...)
+		}
+	}
+	rr.registrations = nil
+	rr.replayed = true
+}
+
+// Parallel runs builders in their own goroutines, each with its own Registrar.
+// Once all builders are done, their registrations are made with r in
+// builders order, so the result is the same as building sequentially.
+// Elements are returned in builders order
+func Parallel(r Registrar, builders ...func(r Registrar) Element) []Element {
+	elements := make([]Element, len(builders))
+	recorders := make([]*recordingRegistrar, len(builders))
+
+	wg := &sync.WaitGroup{}
+	for ii, builder := range builders {
+		recorders[ii] = &recordingRegistrar{target: r}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			elements[ii] = builder(recorders[ii])
+		}()
+	}
+	wg.Wait()
+
+	for _, rr := range recorders {
+		rr.replay()
+	}
+
+	return elements
+}
@@ -0,0 +1,123 @@
+package compton
+
+import (
+	"github.com/boggydigital/compton/consts/attr"
+	"github.com/boggydigital/compton/consts/compton_atoms"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"io"
+	"strings"
+)
+
+// https://html.spec.whatwg.org/multipage/syntax.html#void-elements
+var voidElements = map[atom.Atom]any{
+	atom.Area:   nil,
+	atom.Base:   nil,
+	atom.Br:     nil,
+	atom.Col:    nil,
+	atom.Embed:  nil,
+	atom.Hr:     nil,
+	atom.Img:    nil,
+	atom.Input:  nil,
+	atom.Link:   nil,
+	atom.Meta:   nil,
+	atom.Source: nil,
+	atom.Track:  nil,
+	atom.Wbr:    nil,
+}
+
+// Parse reads HTML fragment (in the context of a body element)
+// and converts it into Elements that can be appended to a page.
+// Comments are not preserved
+func Parse(r io.Reader) ([]Element, error) {
+	bodyContext := &html.Node{
+		Type:     html.ElementNode,
+		Data:     atom.Body.String(),
+		DataAtom: atom.Body,
+	}
+
+	nodes, err := html.ParseFragment(r, bodyContext)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]Element, 0, len(nodes))
+	for _, node := range nodes {
+		if el := convertNode(node); el != nil {
+			elements = append(elements, el)
+		}
+	}
+
+	return elements, nil
+}
+
+func convertNode(node *html.Node) Element {
+	switch node.Type {
+	case html.TextNode:
+		return Text(node.Data)
+	case html.ElementNode:
+		return convertElementNode(node)
+	default:
+		return nil
+	}
+}
+
+func convertElementNode(node *html.Node) Element {
+
+	var el Element
+
+	a := node.DataAtom
+	if a == 0 {
+		a = compton_atoms.Stoa(node.Data)
+	}
+
+	switch a {
+	case atom.Script:
+		el = Script([]byte(nodeText(node)))
+	case atom.Style:
+		el = Style([]byte(nodeText(node)))
+	case 0:
+		// unknown custom elements keep their tag name in markup
+		el = NewElement(BytesMarkup(0, []byte(strings.Replace(tacMarkupTemplate, "{tag}", node.Data, -1))))
+	default:
+		if _, ok := voidElements[a]; ok {
+			el = NewElement(voidTacMarkup(a))
+		} else {
+			el = NewElement(tacMarkup(a))
+		}
+	}
+
+	for _, at := range node.Attr {
+		name := at.Key
+		if at.Namespace != "" {
+			name = at.Namespace + ":" + name
+		}
+		if name == attr.Class {
+			el.AddClass(strings.Fields(at.Val)...)
+		} else {
+			el.SetAttribute(name, at.Val)
+		}
+	}
+
+	if a == atom.Script || a == atom.Style {
+		return el
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if ce := convertNode(child); ce != nil {
+			el.Append(ce)
+		}
+	}
+
+	return el
+}
+
+func nodeText(node *html.Node) string {
+	sb := strings.Builder{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			sb.WriteString(child.Data)
+		}
+	}
+	return sb.String()
+}
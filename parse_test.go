@@ -0,0 +1,85 @@
+package compton
+
+import (
+	"bytes"
+	"github.com/boggydigital/compton/consts/compton_atoms"
+	"golang.org/x/net/html/atom"
+	"strings"
+	"testing"
+)
+
+func writeElements(t *testing.T, elements ...Element) string {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	for _, el := range elements {
+		if err := el.Write(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.String()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"empty", "", ""},
+		{"text", "text", "text"},
+		{"escaped text", "a &amp; b", "a &amp; b"},
+		{"element", "<div>text</div>", "<div >text</div>"},
+		{"attributes", `<a href="/path" title="t">link</a>`, "<a href='/path' title='t'>link</a>"},
+		{"classes", `<span class="a  b c">s</span>`, "<span class='a b c'>s</span>"},
+		{"nested", "<ul><li>1</li><li>2</li></ul>", "<ul ><li >1</li><li >2</li></ul>"},
+		{"siblings", "<b>b</b> <i>i</i>", "<b >b</b> <i >i</i>"},
+		{"void", `<br><img src="/i.png"><input type="text">`, "<br /><img src='/i.png'/><input type='text'/>"},
+		{"comment", "<!-- comment --><p>p</p>", "<p >p</p>"},
+		{"script", "<script>let a = 1 < 2;</script>", "<script >let a = 1 < 2;</script>"},
+		{"style", "<style>a > b {}</style>", "<style >a > b {}</style>"},
+		{"unsafe url", `<a href="javascript:alert(1)">a</a>`, "<a href='#ZgotmplZ'>a</a>"},
+		{"compton atom", "<flex-items>f</flex-items>", "<flex-items >f</flex-items>"},
+		{"custom element", `<my-element data-a="a">c</my-element>`, "<my-element data-a='a'>c</my-element>"},
+		{"unclosed", "<div><p>p", "<div ><p >p</p></div>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elements, err := Parse(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := writeElements(t, elements...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTagNames(t *testing.T) {
+	tests := []struct {
+		html string
+		want atom.Atom
+	}{
+		{"<div></div>", atom.Div},
+		{"<img>", atom.Img},
+		{"<script></script>", atom.Script},
+		{"<flex-items></flex-items>", compton_atoms.FlexItems},
+		{"<my-element></my-element>", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.html, func(t *testing.T) {
+			elements, err := Parse(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(elements) != 1 {
+				t.Fatalf("got %d elements, want 1", len(elements))
+			}
+			if got := elements[0].GetTagName(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
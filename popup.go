@@ -18,6 +18,12 @@ type PopupElement struct {
 	*BaseElement
 }
 
+func (pe *PopupElement) Clone() Element {
+	return &PopupElement{
+		BaseElement: pe.BaseElement.cloneBase(),
+	}
+}
+
 func Attach(r Registrar, actor, target Element) *PopupElement {
 	pe := &PopupElement{
 		BaseElement: NewElement(contentMarkup(compton_atoms.Popup)),
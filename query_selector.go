@@ -0,0 +1,322 @@
+package compton
+
+import (
+	"errors"
+	"github.com/boggydigital/compton/consts/attr"
+	"github.com/boggydigital/compton/consts/compton_atoms"
+	"golang.org/x/net/html/atom"
+	"strings"
+)
+
+const (
+	descendantCombinator = ' '
+	childCombinator      = '>'
+)
+
+const firstChildPseudoClass = "first-child"
+
+var ErrInvalidSelector = errors.New("invalid selector")
+
+type attributeSelector struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+type compoundSelector struct {
+	tag        atom.Atom
+	hasTag     bool
+	id         string
+	classes    []string
+	attributes []attributeSelector
+	firstChild bool
+	// combinator is the relation to the previous compound selector
+	combinator byte
+}
+
+type complexSelector []*compoundSelector
+
+type Selector []complexSelector
+
+type selectorNode struct {
+	element    Element
+	firstChild bool
+}
+
+// ParseSelector supports a subset of CSS selectors:
+// - type: div, flex-items, title-values, *
+// - id: #id
+// - class: .class
+// - attribute: [attr], [attr=value], [attr="value"]
+// - pseudo-class: :first-child
+// - combinators: descendant (whitespace), child (>)
+// - selector lists: a, b
+//
+// Selectors match the element tree, not the written markup: children are
+// content children followed by slots elements (see GetChildren), so :first-child
+// and > can differ from the written document, when markup writes slots before
+// the content or inside the markup elements
+func ParseSelector(s string) (Selector, error) {
+	var selector Selector
+	for _, part := range splitSelectorList(s) {
+		cs, err := parseComplexSelector(part)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, cs)
+	}
+	return selector, nil
+}
+
+// splitSelectorList splits selector list on commas,
+// that are not inside attribute selectors
+func splitSelectorList(s string) []string {
+	var parts []string
+	start := 0
+	for ii := 0; ii < len(s); ii++ {
+		switch s[ii] {
+		case '[':
+			if end := scanAttributeSelectorEnd(s[ii:]); end >= 0 {
+				ii += end
+			}
+		case ',':
+			parts = append(parts, s[start:ii])
+			start = ii + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// scanAttributeSelectorEnd returns index of the closing bracket of
+// the attribute selector at the start of s, skipping quoted values
+func scanAttributeSelectorEnd(s string) int {
+	var quote byte
+	for ii := 1; ii < len(s); ii++ {
+		switch c := s[ii]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ']':
+			return ii
+		}
+	}
+	return -1
+}
+
+func parseComplexSelector(s string) (complexSelector, error) {
+	var cs complexSelector
+	var combinator byte = descendantCombinator
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, ErrInvalidSelector
+	}
+
+	for len(s) > 0 {
+		switch s[0] {
+		case ' ', '\t', '\n':
+			s = s[1:]
+		case childCombinator:
+			if len(cs) == 0 || combinator == childCombinator {
+				return nil, ErrInvalidSelector
+			}
+			combinator = childCombinator
+			s = s[1:]
+		default:
+			compound, rest, err := parseCompoundSelector(s)
+			if err != nil {
+				return nil, err
+			}
+			// unsupported characters (e.g. ~ and + combinators)
+			if len(rest) == len(s) {
+				return nil, ErrInvalidSelector
+			}
+			compound.combinator = combinator
+			cs = append(cs, compound)
+			combinator = descendantCombinator
+			s = rest
+		}
+	}
+
+	if combinator == childCombinator {
+		return nil, ErrInvalidSelector
+	}
+
+	return cs, nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '-' || c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+func scanName(s string) (string, string) {
+	ii := 0
+	for ii < len(s) && isNameChar(s[ii]) {
+		ii++
+	}
+	return s[:ii], s[ii:]
+}
+
+func parseCompoundSelector(s string) (*compoundSelector, string, error) {
+	compound := &compoundSelector{}
+
+	if s[0] == '*' {
+		s = s[1:]
+	} else if isNameChar(s[0]) {
+		var name string
+		name, s = scanName(s)
+		compound.hasTag = true
+		compound.tag = compton_atoms.Stoa(strings.ToLower(name))
+	}
+
+	for len(s) > 0 {
+		var name string
+		switch s[0] {
+		case '#':
+			if name, s = scanName(s[1:]); name == "" {
+				return nil, s, ErrInvalidSelector
+			}
+			compound.id = name
+		case '.':
+			if name, s = scanName(s[1:]); name == "" {
+				return nil, s, ErrInvalidSelector
+			}
+			compound.classes = append(compound.classes, name)
+		case ':':
+			if name, s = scanName(s[1:]); name != firstChildPseudoClass {
+				return nil, s, ErrInvalidSelector
+			}
+			compound.firstChild = true
+		case '[':
+			end := scanAttributeSelectorEnd(s)
+			if end < 0 {
+				return nil, s, ErrInvalidSelector
+			}
+			as, err := parseAttributeSelector(s[1:end])
+			if err != nil {
+				return nil, s, err
+			}
+			compound.attributes = append(compound.attributes, as)
+			s = s[end+1:]
+		default:
+			return compound, s, nil
+		}
+	}
+
+	return compound, s, nil
+}
+
+func parseAttributeSelector(s string) (attributeSelector, error) {
+	as := attributeSelector{}
+	name, value, hasValue := strings.Cut(s, "=")
+	as.name = strings.TrimSpace(name)
+	if as.name == "" {
+		return as, ErrInvalidSelector
+	}
+	if hasValue {
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		as.value = value
+		as.hasValue = true
+	}
+	return as, nil
+}
+
+func (cs *compoundSelector) matches(sn selectorNode) bool {
+	el := sn.element
+	if cs.hasTag && el.GetTagName() != cs.tag {
+		return false
+	}
+	if cs.id != "" && el.GetAttribute(attr.Id) != cs.id {
+		return false
+	}
+	if len(cs.classes) > 0 && !el.HasClass(cs.classes...) {
+		return false
+	}
+	for _, as := range cs.attributes {
+		if as.name == attr.Class {
+			if as.hasValue && !el.HasClass(strings.Fields(as.value)...) {
+				return false
+			}
+			continue
+		}
+		if !el.HasAttribute(as.name) {
+			return false
+		}
+		if as.hasValue && el.GetAttribute(as.name) != as.value {
+			return false
+		}
+	}
+	if cs.firstChild && !sn.firstChild {
+		return false
+	}
+	return true
+}
+
+// matchesFrom matches compound selector at index ci against path node at index pi,
+// then continues to the left, following combinators
+func (cs complexSelector) matchesFrom(ci int, path []selectorNode, pi int) bool {
+	if !cs[ci].matches(path[pi]) {
+		return false
+	}
+	if ci == 0 {
+		return true
+	}
+	switch cs[ci].combinator {
+	case childCombinator:
+		return pi > 0 && cs.matchesFrom(ci-1, path, pi-1)
+	default:
+		for pj := pi - 1; pj >= 0; pj-- {
+			if cs.matchesFrom(ci-1, path, pj) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s Selector) matches(path []selectorNode) bool {
+	for _, cs := range s {
+		if cs.matchesFrom(len(cs)-1, path, len(path)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTextNode(el Element) bool {
+	return el.GetTagName() == atom.Plaintext
+}
+
+// query walks children (and their descendants) of the last path element
+// in document order and returns matches. When first is set, walking stops on the first match
+func (s Selector) query(path []selectorNode, children []Element, first bool) []Element {
+	matches := make([]Element, 0)
+	firstChild := true
+	for _, child := range children {
+		if isTextNode(child) {
+			continue
+		}
+		childPath := append(path, selectorNode{element: child, firstChild: firstChild})
+		firstChild = false
+		if s.matches(childPath) {
+			matches = append(matches, child)
+			if first {
+				return matches
+			}
+		}
+		matches = append(matches, s.query(childPath, child.GetChildren(), first)...)
+		if first && len(matches) > 0 {
+			return matches[:1]
+		}
+	}
+	return matches
+}
@@ -0,0 +1,145 @@
+package compton
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const querySelectorHtml = `<header id="h" class="top"><h1 id="h1">title</h1></header>
+<main id="m">
+	<section id="s1" class="card wide" data-kind="a,b">
+		<p id="p1" class="text">one</p>
+		<p id="p2">two <a id="a1" href="/x" title="x]y">link</a></p>
+	</section>
+	<section id="s2" class="card" data-kind="c">
+		<span id="sp1">span</span>
+		<p id="p3" class="text">three</p>
+	</section>
+	<flex-items id="f"><p id="p4">four</p></flex-items>
+</main>`
+
+func querySelectorRoot(t *testing.T) Element {
+	t.Helper()
+	elements, err := Parse(strings.NewReader(querySelectorHtml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := Div()
+	root.Append(elements...)
+	return root
+}
+
+func elementIds(elements []Element) []string {
+	ids := make([]string, 0, len(elements))
+	for _, el := range elements {
+		ids = append(ids, el.GetAttribute("id"))
+	}
+	return ids
+}
+
+func TestQuerySelectorAll(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"header", []string{"h"}},
+		{"P", []string{"p1", "p2", "p3", "p4"}},
+		{"flex-items", []string{"f"}},
+		{"#p2", []string{"p2"}},
+		{"p#p2", []string{"p2"}},
+		{"span#p2", []string{}},
+		{".card", []string{"s1", "s2"}},
+		{".card.wide", []string{"s1"}},
+		{"section.card.text", []string{}},
+		{"[href]", []string{"a1"}},
+		{"[data-kind=c]", []string{"s2"}},
+		{`[data-kind="a,b"]`, []string{"s1"}},
+		{`[title='x]y']`, []string{"a1"}},
+		{"[data-kind=a]", []string{}},
+		{"section p", []string{"p1", "p2", "p3"}},
+		{"main a", []string{"a1"}},
+		{"main > p", []string{}},
+		{"flex-items > p", []string{"p4"}},
+		{"section > p > a", []string{"a1"}},
+		{"main>section>p.text", []string{"p1", "p3"}},
+		{"p:first-child", []string{"p1", "p4"}},
+		{"section :first-child", []string{"p1", "a1", "sp1"}},
+		{"section > *", []string{"p1", "p2", "sp1", "p3"}},
+		{"h1, a", []string{"h1", "a1"}},
+		{"a, h1", []string{"h1", "a1"}},
+		{"#p1, .text", []string{"p1", "p3"}},
+		{`[data-kind="a,b"], #h`, []string{"h", "s1"}},
+	}
+
+	root := querySelectorRoot(t)
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got := elementIds(root.QuerySelectorAll(tt.selector))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuerySelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{"p", "p1"},
+		{".text", "p1"},
+		{"section:first-child", "s1"},
+		{"span:first-child", "sp1"},
+		{"section + p", ""},
+		{"table", ""},
+	}
+
+	root := querySelectorRoot(t)
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			var got string
+			if el := root.QuerySelector(tt.selector); el != nil {
+				got = el.GetAttribute("id")
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSelectorInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		" ",
+		"div,",
+		", div",
+		"div,,p",
+		"#",
+		".",
+		"div.",
+		"[",
+		"[]",
+		"[=a]",
+		`[title="a]`,
+		"a:hover",
+		"> p",
+		"div >",
+		"div > > p",
+		"div ~ p",
+		"div + p",
+	}
+
+	for _, selector := range tests {
+		t.Run(selector, func(t *testing.T) {
+			if _, err := ParseSelector(selector); !errors.Is(err, ErrInvalidSelector) {
+				t.Errorf("got %v, want %v", err, ErrInvalidSelector)
+			}
+		})
+	}
+}
@@ -9,3 +9,57 @@ type Registrar interface {
 	RegisterRequirements(name string, elements ...Element)
 	RegisterDeferrals(name string, elements ...Element)
 }
+
+// registryChecker is implemented by Registrars that can tell whether the name
+// is registered already, to report registrations that would be ignored
+type registryChecker interface {
+	IsRegistered(name string) bool
+}
+
+// registrarBinder is implemented by components that keep the Registrar they were
+// created with, for the clones of the page (fragment, shadow host) to rebind them
+type registrarBinder interface {
+	bindRegistrar(registrars map[Registrar]Registrar)
+}
+
+// boundRegistrar returns clone of the Registrar, if it's been cloned
+func boundRegistrar(r Registrar, registrars map[Registrar]Registrar) Registrar {
+	if clone, ok := registrars[r]; ok {
+		return clone
+	}
+	return r
+}
+
+// rebindRegistrars points components of the cloned tree, that keep
+// Registrars of the original tree (e.g. the page), to their clones
+func rebindRegistrars(original, clone Element) {
+	registrars := make(map[Registrar]Registrar)
+	mapRegistrars(original, clone, registrars)
+	bindRegistrars(clone, registrars)
+}
+
+// mapRegistrars matches Registrars of the original tree to the clones,
+// clone children are in the same order as the original children
+func mapRegistrars(original, clone Element, registrars map[Registrar]Registrar) {
+	if originalRegistrar, ok := original.(Registrar); ok {
+		if cloneRegistrar, ok := clone.(Registrar); ok {
+			registrars[originalRegistrar] = cloneRegistrar
+		}
+	}
+	originalChildren, cloneChildren := original.GetChildren(), clone.GetChildren()
+	if len(originalChildren) != len(cloneChildren) {
+		return
+	}
+	for ii := range originalChildren {
+		mapRegistrars(originalChildren[ii], cloneChildren[ii], registrars)
+	}
+}
+
+func bindRegistrars(el Element, registrars map[Registrar]Registrar) {
+	if rb, ok := el.(registrarBinder); ok {
+		rb.bindRegistrar(registrars)
+	}
+	for _, child := range el.GetChildren() {
+		bindRegistrars(child, registrars)
+	}
+}
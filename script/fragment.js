@@ -0,0 +1,63 @@
+const fragmentNonce = document.currentScript ? document.currentScript.nonce : "";
+
+// fetchFragment requests fragment at url and swaps it into the target element
+const fetchFragment = async (url) => {
+    let response = await fetch(url, {headers: {"Accept": "text/html"}});
+    if (!response.ok) {
+        throw new Error(url + ": " + response.status + " " + response.statusText);
+    }
+    let template = document.createElement("template");
+    template.innerHTML = await response.text();
+    template.content.querySelectorAll("fragment[data-target]").forEach(swapFragment);
+}
+
+const swapFragment = (fragment) => {
+    let target = document.getElementById(fragment.getAttribute("data-target"));
+    if (!target) {
+        return
+    }
+
+    fragment.querySelectorAll(":scope > style").forEach(adoptStyle);
+
+    let requirements = fragment.querySelector(":scope > requirements");
+    if (requirements) {
+        document.body.prepend(...Array.from(requirements.childNodes).map(recreateScript));
+    }
+
+    let content = fragment.querySelector(":scope > content");
+    if (content) {
+        target.replaceChildren(...content.childNodes);
+    }
+
+    let deferrals = fragment.querySelector(":scope > deferrals");
+    if (deferrals) {
+        document.body.append(...Array.from(deferrals.childNodes).map(recreateScript));
+    }
+}
+
+// styles are adopted as constructed stylesheets, that are
+// not blocked by the page policy inline styles hashes
+const adoptStyle = (style) => {
+    let sheet = new CSSStyleSheet();
+    sheet.replaceSync(style.textContent);
+    document.adoptedStyleSheets = [...document.adoptedStyleSheets, sheet];
+}
+
+// scripts inserted as markup are not executed, so they're re-created with
+// the nonce of this script. Page policy with inline scripts hashes doesn't
+// allow fragment scripts, so they need page policy nonce
+const recreateScript = (node) => {
+    if (node.nodeName !== "SCRIPT") {
+        return node
+    }
+    if (!fragmentNonce) {
+        throw new Error("fragment scripts require page Content-Security-Policy nonce");
+    }
+    let element = document.createElement("script");
+    for (const attr of node.attributes) {
+        element.setAttribute(attr.name, attr.value);
+    }
+    element.textContent = node.textContent;
+    element.nonce = fragmentNonce;
+    return element
+}
@@ -0,0 +1,7 @@
+document.querySelectorAll("template[data-suspense]").forEach(t => {
+    let placeholder = document.getElementById(t.getAttribute("data-suspense"));
+    if (placeholder) {
+        placeholder.replaceWith(t.content);
+    }
+    t.remove();
+});
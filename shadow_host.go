@@ -0,0 +1,143 @@
+package compton
+
+import (
+	"embed"
+	"github.com/boggydigital/compton/consts/class"
+	"github.com/boggydigital/compton/consts/compton_atoms"
+	"golang.org/x/net/html/atom"
+	"io"
+	"maps"
+	"slices"
+)
+
+const shadowRootModeAttr = "shadowrootmode"
+
+// ShadowHostElement renders content inside a declarative shadow root
+// (https://developer.mozilla.org/en-US/docs/Web/HTML/Element/template#shadowrootmode).
+// ShadowHostElement is a Registrar: components created with it as a Registrar
+// get their styles and requirements scoped inside the shadow root, instead of
+// the page head. Deferrals are registered with the page Registrar.
+// Utility classes used by the shadow root content are written inside the shadow root
+type ShadowHostElement struct {
+	*BaseElement
+	r            Registrar
+	registry     map[string]any
+	template     Element
+	styles       Element
+	styleClasses Element
+	requirements Element
+	content      Element
+}
+
+func (she *ShadowHostElement) Clone() Element {
+	clone := &ShadowHostElement{
+		BaseElement: she.BaseElement.cloneBase(),
+		r:           she.r,
+		registry:    maps.Clone(she.registry),
+	}
+	clone.template = clone.Children[0]
+	if parts := clone.template.GetChildren(); len(parts) == 4 {
+		clone.styles, clone.styleClasses, clone.requirements, clone.content = parts[0], parts[1], parts[2], parts[3]
+	}
+	rebindRegistrars(she, clone)
+	return clone
+}
+
+func (she *ShadowHostElement) bindRegistrar(registrars map[Registrar]Registrar) {
+	she.r = boundRegistrar(she.r, registrars)
+}
+
+func (she *ShadowHostElement) Append(children ...Element) {
+	she.content.Append(children...)
+}
+
+func (she *ShadowHostElement) Prepend(children ...Element) {
+	she.content.Prepend(children...)
+}
+
+func (she *ShadowHostElement) InsertBefore(newChild, refChild Element) bool {
+	return she.content.InsertBefore(newChild, refChild)
+}
+
+func (she *ShadowHostElement) RemoveChild(child Element) bool {
+	return she.content.RemoveChild(child)
+}
+
+func (she *ShadowHostElement) ReplaceChild(newChild, oldChild Element) bool {
+	return she.content.ReplaceChild(newChild, oldChild)
+}
+
+func (she *ShadowHostElement) Write(w io.Writer) error {
+	classNames := collectClassNames(she.content)
+	classNames = append(classNames, collectClassNames(she.requirements)...)
+	for _, child := range slices.Clone(she.styleClasses.GetChildren()) {
+		she.styleClasses.RemoveChild(child)
+	}
+	if sc := class.StyleClassesFor(classNames...); len(sc) > 0 {
+		she.styleClasses.Append(Style(sc))
+	}
+	return she.BaseElement.Write(w)
+}
+
+func (she *ShadowHostElement) RegisterStyles(efs embed.FS, names ...string) {
+	for _, name := range names {
+		if _, ok := she.registry[name]; !ok {
+			she.registry[name] = nil
+			if content, err := readStyle(efs, name); err == nil {
+				if len(content) > 0 {
+					she.styles.Append(Style(content))
+				}
+			} else {
+				reportRegistrationError(she.r, ErrRegistration(name, err))
+			}
+		}
+	}
+}
+
+func (she *ShadowHostElement) RegisterRequirements(name string, elements ...Element) {
+	if _, ok := she.registry[name]; !ok {
+		she.registry[name] = nil
+		she.requirements.Append(elements...)
+	}
+}
+
+// IsRegistered checks own styles and requirements, deferrals are registered with the page Registrar
+func (she *ShadowHostElement) IsRegistered(name string) bool {
+	if _, ok := she.registry[name]; ok {
+		return true
+	}
+	rc, ok := she.r.(registryChecker)
+	return ok && rc.IsRegistered(name)
+}
+
+func (she *ShadowHostElement) registrationError(err error) {
+	reportRegistrationError(she.r, err)
+}
+
+func (she *ShadowHostElement) RegisterDeferrals(name string, elements ...Element) {
+	she.r.RegisterDeferrals(name, elements...)
+}
+
+func ShadowHost(r Registrar, mode EncapsulationMode) *ShadowHostElement {
+	she := &ShadowHostElement{
+		BaseElement:  NewElement(tacMarkup(compton_atoms.ShadowHost)),
+		r:            r,
+		registry:     make(map[string]any),
+		template:     AtomicElement(atom.Template),
+		styles:       NewElement(contentMarkup(compton_atoms.Placeholder)),
+		styleClasses: NewElement(contentMarkup(compton_atoms.Placeholder)),
+		requirements: Requirements(),
+		content:      Content(),
+	}
+
+	she.template.SetAttribute(shadowRootModeAttr, string(mode))
+	she.template.Append(she.styles, she.styleClasses, she.requirements, she.content)
+	she.BaseElement.Append(she.template)
+
+	// page styles that define custom properties are inherited by the shadow root,
+	// the rest of the page styles need to be included
+	she.RegisterStyles(DefaultStyle,
+		"style/page.css", compton_atoms.StyleName(compton_atoms.ShadowHost))
+
+	return she
+}
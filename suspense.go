@@ -0,0 +1,304 @@
+package compton
+
+import (
+	"context"
+	"embed"
+	_ "embed"
+	"errors"
+	"github.com/boggydigital/compton/consts/attr"
+	"github.com/boggydigital/compton/consts/compton_atoms"
+	"golang.org/x/net/html/atom"
+	"io"
+	"sync"
+	"time"
+)
+
+const suspenseTemplateAttr = "data-suspense"
+
+var (
+	//go:embed "script/suspense.js"
+	scriptSuspense []byte
+)
+
+var errSuspenseIdRegistered = errors.New("suspense id is already registered")
+
+// SuspenseElement writes placeholder in place and the produced content
+// at the end of the page, as a deferral: template with the content and
+// a script that replaces placeholder with that content.
+// When the content takes longer than timeout, fallback is used instead
+type SuspenseElement struct {
+	*BaseElement
+	timeout  time.Duration
+	fallback Element
+}
+
+func (se *SuspenseElement) Clone() Element {
+	clone := &SuspenseElement{
+		BaseElement: se.BaseElement.cloneBase(),
+		timeout:     se.timeout,
+	}
+	if se.fallback != nil {
+		clone.fallback = se.fallback.Clone()
+	}
+	return clone
+}
+
+func (se *SuspenseElement) Timeout(timeout time.Duration, fallback Element) *SuspenseElement {
+	se.timeout = timeout
+	se.fallback = fallback
+	return se
+}
+
+// content waits for the produced element or timeout
+// and returns template with that element and the script.
+// Producer context is cancelled on timeout, registrations
+// made by the producer after that are ignored
+func (se *SuspenseElement) content(r Registrar, id string, produce func(ctx context.Context, r Registrar) Element) Element {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if se.timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), se.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+
+	cr := &cancelRegistrar{target: r, ctx: ctx}
+
+	produced := make(chan Element, 1)
+	go func() {
+		produced <- produce(ctx, cr)
+	}()
+
+	var el Element
+	select {
+	case el = <-produced:
+	case <-ctx.Done():
+		el = se.fallback
+	}
+	cr.cancel()
+
+	template := AtomicElement(atom.Template)
+	template.SetAttribute(suspenseTemplateAttr, id)
+	if el != nil {
+		template.Append(el)
+	}
+
+	content := NewElement(contentMarkup(compton_atoms.Placeholder))
+	content.Append(template, Script(scriptSuspense))
+	return content
+}
+
+// cancelRegistrar forwards registrations of the suspense producer,
+// until the suspense content is done or has timed out
+type cancelRegistrar struct {
+	target    Registrar
+	ctx       context.Context
+	cancelled bool
+	mtx       sync.Mutex
+}
+
+func (cr *cancelRegistrar) active() bool {
+	cr.mtx.Lock()
+	defer cr.mtx.Unlock()
+
+	return !cr.cancelled && cr.ctx.Err() == nil
+}
+
+func (cr *cancelRegistrar) cancel() {
+	cr.mtx.Lock()
+	defer cr.mtx.Unlock()
+
+	cr.cancelled = true
+}
+
+func (cr *cancelRegistrar) RegisterStyles(efs embed.FS, names ...string) {
+	if cr.active() {
+		cr.target.RegisterStyles(efs, names...)
+	}
+}
+
+func (cr *cancelRegistrar) RegisterRequirements(name string, elements ...Element) {
+	if cr.active() {
+		cr.target.RegisterRequirements(name, elements...)
+	}
+}
+
+func (cr *cancelRegistrar) RegisterDeferrals(name string, elements ...Element) {
+	if cr.active() {
+		cr.target.RegisterDeferrals(name, elements...)
+	}
+}
+
+func (cr *cancelRegistrar) IsRegistered(name string) bool {
+	rc, ok := cr.target.(registryChecker)
+	return ok && rc.IsRegistered(name)
+}
+
+func (cr *cancelRegistrar) registrationError(err error) {
+	if cr.active() {
+		reportRegistrationError(cr.target, err)
+	}
+}
+
+func (cr *cancelRegistrar) streamElements(el Element) ([]Element, []Element) {
+	if sr, ok := cr.target.(streamRegistrar); ok {
+		return sr.streamElements(el)
+	}
+	return nil, nil
+}
+
+// suspenseDeferred is the deferred suspense content. Suspense contents are
+// written by the suspenseGroup, that precedes them in the deferrals
+type suspenseDeferred struct {
+	*DeferredElement
+	se       *SuspenseElement
+	id       string
+	producer func(ctx context.Context, r Registrar) Element
+	grouped  bool
+}
+
+// Clone produces the content with the clone Registrar (see bindRegistrar)
+func (sd *suspenseDeferred) Clone() Element {
+	clone := &suspenseDeferred{
+		DeferredElement: sd.DeferredElement.Clone().(*DeferredElement),
+		se:              sd.se,
+		id:              sd.id,
+		producer:        sd.producer,
+	}
+	if clone.DeferredElement.produce != nil {
+		clone.DeferredElement.produce = clone.content
+	}
+	return clone
+}
+
+func (sd *suspenseDeferred) content() Element {
+	return sd.se.content(sd.r, sd.id, sd.producer)
+}
+
+func (sd *suspenseDeferred) Write(w io.Writer) error {
+	if sd.grouped {
+		return nil
+	}
+	return sd.DeferredElement.Write(w)
+}
+
+// suspenseGroup writes contents of the suspense deferrals that follow it
+// in completion order, so that a slow suspense doesn't hold back the others
+type suspenseGroup struct {
+	*BaseElement
+	r Registrar
+}
+
+func (sg *suspenseGroup) Clone() Element {
+	return &suspenseGroup{BaseElement: sg.BaseElement.cloneBase(), r: sg.r}
+}
+
+func (sg *suspenseGroup) bindRegistrar(registrars map[Registrar]Registrar) {
+	sg.r = boundRegistrar(sg.r, registrars)
+}
+
+type suspenseReceived struct {
+	de *DeferredElement
+	el Element
+}
+
+func (sg *suspenseGroup) Write(w io.Writer) error {
+	if sg.Parent() == nil {
+		return nil
+	}
+
+	var pending []*DeferredElement
+	for _, el := range sg.Parent().GetChildren() {
+		sd, ok := el.(*suspenseDeferred)
+		if !ok {
+			continue
+		}
+		sd.grouped = true
+		sd.start()
+		if sd.elements == nil {
+			// resolved (e.g. when the page is not streamed)
+			if err := sd.DeferredElement.Write(w); err != nil {
+				return err
+			}
+		} else {
+			pending = append(pending, sd.DeferredElement)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	received := make(chan suspenseReceived)
+	wg := &sync.WaitGroup{}
+	for _, de := range pending {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for el := range de.elements {
+				if el != nil {
+					received <- suspenseReceived{de: de, el: el}
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(received)
+	}()
+
+	flush(w)
+
+	for sr := range received {
+		sr.de.BaseElement.Append(sr.el)
+		if err := writeReceived(w, sg.r, sr.el); err != nil {
+			// let the receiving goroutines complete
+			go func() {
+				for range received {
+				}
+			}()
+			return err
+		}
+	}
+
+	for _, de := range pending {
+		de.done()
+	}
+	return nil
+}
+
+// Suspense creates element with the placeholder, that is replaced with
+// the produced element, once the page has been written (or streamed).
+// Streamed suspense contents are written in the order they complete.
+// Producer gets the context, that is cancelled on timeout, and the Registrar
+// to use for the content, that ignores registrations after the timeout.
+// Suspense ids need to be unique, duplicate ids are registration errors
+func Suspense(r Registrar, id string, placeholder Element, produce func(ctx context.Context, r Registrar) Element) *SuspenseElement {
+	se := &SuspenseElement{
+		BaseElement: NewElement(tacMarkup(compton_atoms.Suspense)),
+	}
+	se.SetAttribute(attr.Id, id)
+	if placeholder != nil {
+		se.Append(placeholder)
+	}
+
+	name := compton_atoms.Atos(compton_atoms.Suspense) + "-" + id
+	if rc, ok := r.(registryChecker); ok && rc.IsRegistered(name) {
+		reportRegistrationError(r, ErrRegistration(name, errSuspenseIdRegistered))
+		return se
+	}
+
+	r.RegisterStyles(DefaultStyle, compton_atoms.StyleName(compton_atoms.Suspense))
+	r.RegisterDeferrals(compton_atoms.Atos(compton_atoms.Suspense),
+		&suspenseGroup{BaseElement: NewElement(contentMarkup(compton_atoms.Placeholder)), r: r})
+	sd := &suspenseDeferred{
+		se:       se,
+		id:       id,
+		producer: produce,
+	}
+	sd.DeferredElement = Deferred(r, sd.content)
+	r.RegisterDeferrals(name, sd)
+
+	return se
+}
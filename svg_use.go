@@ -1,12 +1,10 @@
 package compton
 
 import (
-	"bytes"
 	_ "embed"
 	"github.com/boggydigital/compton/consts/class"
 	"github.com/boggydigital/compton/consts/color"
 	"github.com/boggydigital/compton/consts/compton_atoms"
-	"io"
 )
 
 type Symbol int
@@ -36,6 +34,8 @@ var symbolStrings = map[Symbol]string{
 	Circle:  "circle",
 }
 
+const symbolSlot = "symbol"
+
 var (
 	//go:embed "markup/atlas.html"
 	markupAtlas string
@@ -46,28 +46,11 @@ type SvgUseElement struct {
 	s Symbol
 }
 
-func (sue *SvgUseElement) Write(w io.Writer) error {
-	bts, err := sue.BaseElement.MarkupProvider.GetMarkup()
-	if err != nil {
-		return err
-	}
-	return WriteContents(bytes.NewReader(bts), w, sue.WriteFragment)
-}
-
-func (sue *SvgUseElement) WriteFragment(t string, w io.Writer) error {
-	switch t {
-	case ".Symbol":
-		if _, err := io.WriteString(w, symbolStrings[sue.s]); err != nil {
-			return err
-		}
-	case AttributesToken:
-		if err := sue.BaseElement.WriteFragment(t, w); err != nil {
-			return err
-		}
-	default:
-		return ErrUnknownToken(t)
+func (sue *SvgUseElement) Clone() Element {
+	return &SvgUseElement{
+		BaseElement: sue.BaseElement.cloneBase(),
+		s:           sue.s,
 	}
-	return nil
 }
 
 func (sue *SvgUseElement) ForegroundColor(c color.Color) *SvgUseElement {
@@ -82,9 +65,10 @@ func SvgUse(r Registrar, s Symbol) *SvgUseElement {
 	}
 
 	sue.AddClass(symbolStrings[s])
+	sue.SetSlot(symbolSlot, Text(symbolStrings[s]))
 
 	r.RegisterStyles(DefaultStyle, compton_atoms.StyleName(compton_atoms.SvgUse))
-	r.RegisterRequirements(compton_atoms.MarkupName(compton_atoms.SvgUse), Text(markupAtlas))
+	r.RegisterRequirements(compton_atoms.MarkupName(compton_atoms.SvgUse), RawHTML(markupAtlas))
 
 	return sue
 }
@@ -11,6 +11,17 @@ type TableElement struct {
 	r Registrar
 }
 
+func (te *TableElement) Clone() Element {
+	return &TableElement{
+		BaseElement: te.BaseElement.cloneBase(),
+		r:           te.r,
+	}
+}
+
+func (te *TableElement) bindRegistrar(registrars map[Registrar]Registrar) {
+	te.r = boundRegistrar(te.r, registrars)
+}
+
 func (te *TableElement) AppendHead(columns ...string) *TableElement {
 
 	var thead Element
@@ -3,6 +3,7 @@ package compton
 import (
 	"github.com/boggydigital/compton/consts/attr"
 	"github.com/boggydigital/compton/consts/input_types"
+	"slices"
 )
 
 type TitleInputElement struct {
@@ -10,6 +11,17 @@ type TitleInputElement struct {
 	input *InputElement
 }
 
+func (ti *TitleInputElement) Clone() Element {
+	clone := &TitleInputElement{
+		TitleValuesElement: ti.TitleValuesElement.clone(),
+	}
+	// input is one of the children, find the clone at the same position
+	if index := slices.Index(ti.Children, Element(ti.input)); index >= 0 {
+		clone.input = clone.Children[index].(*InputElement)
+	}
+	return clone
+}
+
 func (ti *TitleInputElement) SetDatalist(list map[string]string, listId string) *TitleInputElement {
 	ti.input.SetDatalist(list, listId)
 	return ti
@@ -24,7 +36,7 @@ func TISearch(r Registrar, title, inputId string) *TitleInputElement {
 	heading := HeadingText(title, 3)
 	heading.SetId(title)
 	label.Append(heading)
-	titleInput.title = label
+	titleInput.SetSlot(titleSlot, label)
 
 	input := Input(r, input_types.Search)
 	input.SetPlaceholder(title).
@@ -1,7 +1,6 @@
 package compton
 
 import (
-	"bytes"
 	_ "embed"
 	"github.com/boggydigital/compton/consts/align"
 	"github.com/boggydigital/compton/consts/attr"
@@ -10,20 +9,36 @@ import (
 	"github.com/boggydigital/compton/consts/compton_atoms"
 	"github.com/boggydigital/compton/consts/direction"
 	"github.com/boggydigital/compton/consts/size"
-	"io"
 	"maps"
 	"slices"
 )
 
 const LinkTargetTop = "_top"
 
+const titleSlot = "title"
+
 type TitleValuesElement struct {
 	*BaseElement
 	r          Registrar
-	title      Element
 	linkTarget string
 }
 
+func (tve *TitleValuesElement) Clone() Element {
+	return tve.clone()
+}
+
+func (tve *TitleValuesElement) bindRegistrar(registrars map[Registrar]Registrar) {
+	tve.r = boundRegistrar(tve.r, registrars)
+}
+
+func (tve *TitleValuesElement) clone() *TitleValuesElement {
+	return &TitleValuesElement{
+		BaseElement: tve.BaseElement.cloneBase(),
+		r:           tve.r,
+		linkTarget:  tve.linkTarget,
+	}
+}
+
 func (tve *TitleValuesElement) AppendValues(elements ...Element) *TitleValuesElement {
 	if flexItemsElements := tve.GetElementsByTagName(compton_atoms.FlexItems); len(flexItemsElements) > 0 {
 		flexItemsElements[0].Append(elements...)
@@ -71,32 +86,6 @@ func (tve *TitleValuesElement) AppendLinkValues(links map[string]string, order .
 	return tve
 }
 
-func (tve *TitleValuesElement) Write(w io.Writer) error {
-	bts, err := tve.BaseElement.MarkupProvider.GetMarkup()
-	if err != nil {
-		return err
-	}
-	return WriteContents(bytes.NewReader(bts), w, tve.elementFragmentWriter)
-}
-
-func (tve *TitleValuesElement) elementFragmentWriter(t string, w io.Writer) error {
-	switch t {
-	case ".Title":
-		if err := tve.title.Write(w); err != nil {
-			return err
-		}
-	case ContentToken:
-		fallthrough
-	case AttributesToken:
-		if err := tve.BaseElement.WriteFragment(t, w); err != nil {
-			return err
-		}
-	default:
-		return ErrUnknownToken(t)
-	}
-	return nil
-}
-
 func (tve *TitleValuesElement) RowGap(s size.Size) *TitleValuesElement {
 	tve.AddClass(class.RowGap(s))
 	return tve
@@ -108,7 +97,9 @@ func (tve *TitleValuesElement) ForegroundColor(c color.Color) *TitleValuesElemen
 }
 
 func (tve *TitleValuesElement) TitleForegroundColor(c color.Color) *TitleValuesElement {
-	tve.title.AddClass(class.ForegroundColor(c))
+	for _, title := range tve.GetSlot(titleSlot) {
+		title.AddClass(class.ForegroundColor(c))
+	}
 	return tve
 }
 
@@ -116,8 +107,8 @@ func TitleValues(r Registrar, title string) *TitleValuesElement {
 	tve := &TitleValuesElement{
 		BaseElement: NewElement(atomsEmbedMarkup(compton_atoms.TitleValues, DefaultMarkup)),
 		r:           r,
-		title:       HeadingText(title, 3),
 	}
+	tve.SetSlot(titleSlot, HeadingText(title, 3))
 	tve.RowGap(size.Small)
 
 	r.RegisterStyles(DefaultStyle,
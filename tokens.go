@@ -5,4 +5,6 @@ const (
 	DeferralsToken    = ".Deferrals"
 	ContentToken      = ".Content"
 	AttributesToken   = ".Attributes"
+	// SlotTokenPrefix is followed by the slot name, e.g. {{.Slot:header}}
+	SlotTokenPrefix = ".Slot:"
 )
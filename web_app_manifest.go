@@ -0,0 +1,84 @@
+package compton
+
+import (
+	"encoding/json"
+	"github.com/boggydigital/compton/consts/color"
+	"net/http"
+	"strconv"
+)
+
+const manifestContentType = "application/manifest+json"
+
+type ManifestIcon struct {
+	Src     string `json:"src"`
+	Sizes   string `json:"sizes,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+}
+
+type ManifestShortcut struct {
+	Name        string         `json:"name"`
+	ShortName   string         `json:"short_name,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Url         string         `json:"url"`
+	Icons       []ManifestIcon `json:"icons,omitempty"`
+}
+
+// WebAppManifest (https://developer.mozilla.org/en-US/docs/Web/Manifest)
+// is served as http.Handler and linked with PageElement.AppendManifest.
+// Manifest colors use light color scheme values, dark values are used
+// for the page theme-color with the dark color scheme
+type WebAppManifest struct {
+	Name            string
+	ShortName       string
+	Description     string
+	StartUrl        string
+	Scope           string
+	Display         DisplayMode
+	ThemeColor      color.Color
+	BackgroundColor color.Color
+	Icons           []ManifestIcon
+	Shortcuts       []ManifestShortcut
+}
+
+type webAppManifestJson struct {
+	Name            string             `json:"name,omitempty"`
+	ShortName       string             `json:"short_name,omitempty"`
+	Description     string             `json:"description,omitempty"`
+	StartUrl        string             `json:"start_url,omitempty"`
+	Scope           string             `json:"scope,omitempty"`
+	Display         DisplayMode        `json:"display,omitempty"`
+	ThemeColor      string             `json:"theme_color,omitempty"`
+	BackgroundColor string             `json:"background_color,omitempty"`
+	Icons           []ManifestIcon     `json:"icons,omitempty"`
+	Shortcuts       []ManifestShortcut `json:"shortcuts,omitempty"`
+}
+
+func (wam *WebAppManifest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(webAppManifestJson{
+		Name:            wam.Name,
+		ShortName:       wam.ShortName,
+		Description:     wam.Description,
+		StartUrl:        wam.StartUrl,
+		Scope:           wam.Scope,
+		Display:         wam.Display,
+		ThemeColor:      wam.ThemeColor.LightValue(),
+		BackgroundColor: wam.BackgroundColor.LightValue(),
+		Icons:           wam.Icons,
+		Shortcuts:       wam.Shortcuts,
+	})
+}
+
+func (wam *WebAppManifest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bts, err := json.Marshal(wam)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", manifestContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(bts)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(bts)
+}